@@ -1,29 +1,52 @@
 package cli
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
 	fs2 "io/fs"
+	"net/url"
 	"path/filepath"
 	"sort"
 	"strings"
 
 	"github.com/carolynvs/aferox"
 	"github.com/crra/mp3binder/encoding/keyvalue"
+	"github.com/crra/mp3binder/encoding/playlist"
+	"github.com/crra/mp3binder/encoding/tagfile"
+	"github.com/crra/mp3binder/image/cover"
+	"github.com/crra/mp3binder/mp3binder"
+	"github.com/crra/mp3binder/mp3binder/tags"
 	"github.com/crra/mp3binder/slice"
 	"github.com/crra/mp3binder/value"
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"golang.org/x/text/language"
 )
 
 // args is the cobra way of performing checks on the arguments before running                                                                                                                                                                                                                                                                                                                                                                                                                                                the application.
 func (a *application) args(c *cobra.Command, args []string) error {
+	var flags *pflag.FlagSet
+	if c != nil {
+		flags = c.Flags()
+	}
+
+	// the flags set on the command line take precedence over the configs and the job file
+	explicit := explicitFlags(flags)
+
+	configFiles, err := a.applyConfigs(flags, explicit, args)
+	if err != nil {
+		return err
+	}
+
 	if a.verbose {
 		a.statusPrinter = newVerbosePrinter(a.status)
 	}
 
-	var err error
+	for _, file := range configFiles {
+		a.statusPrinter.configFile(file)
+	}
+
 	a.language, err = language.Parse(a.languageStr)
 	if err != nil {
 		return fmt.Errorf("provided language '%s': %w", a.languageStr, ErrUnsupportedLanguage)
@@ -31,18 +54,38 @@ func (a *application) args(c *cobra.Command, args []string) error {
 
 	a.statusPrinter.language(a.language.String())
 
+	if a.chapterTitle != "" {
+		a.chapterTemplate, err = parseChapterTitleTemplate(a.language, a.chapterTitle)
+		if err != nil {
+			return err
+		}
+	}
+
+	// A job file describes the input files and has priority over the arguments.
+	if a.job != nil {
+		if a.inputFile != "" {
+			return fmt.Errorf("the job file '%s' can't be combined with an input file: %w", a.job.file, ErrInvalidJob)
+		}
+
+		a.statusPrinter.jobFile(a.job.file)
+		a.applyJob(explicit)
+		args = a.job.files()
+	}
+
 	// Treat an input file as list of arguments.
+	var inputTitles map[string]string
 	// Any explicitly set argument has order priority over the input file argument.
 	if a.inputFile != "" {
-		argsFromInputFile, err := getInputFileAsList(a.fs, a.inputFile)
+		argsFromInputFile, titles, err := getInputFileAsList(a.fs, a.inputFile)
 		if err != nil {
 			return err
 		}
+		inputTitles = titles
 
 		args = append(args, argsFromInputFile...)
 	}
 
-	mediaFiles, outputCandidateName, err := getMediaFilesFromArguments(a.fs, args)
+	mediaFiles, outputCandidateName, err := getMediaFilesFromArguments(a.fs, a.discoveryNames, args)
 	if err != nil {
 		return err
 	}
@@ -56,7 +99,8 @@ func (a *application) args(c *cobra.Command, args []string) error {
 		return err
 	}
 
-	a.mediaFiles = filterMediaFiles(mediaFiles, a.outputPath)
+	filteredMediaFiles := filterMediaFiles(mediaFiles, a.outputPath)
+	a.mediaFiles = slice.Map(filteredMediaFiles, slice.String[mediaFile])
 
 	if len(a.mediaFiles) == 0 {
 		return ErrNoInput
@@ -66,22 +110,165 @@ func (a *application) args(c *cobra.Command, args []string) error {
 		return ErrAtLeastTwo
 	}
 
+	// titles of the playlist
+	if len(inputTitles) > 0 {
+		a.entries = make([]entry, len(a.mediaFiles))
+		for i, file := range a.mediaFiles {
+			a.entries[i].chapterTitle = inputTitles[file]
+		}
+	}
+
+	if a.job != nil {
+		if len(a.mediaFiles) != len(a.job.Inputs) {
+			return fmt.Errorf("job file '%s': the output file '%s' can't be an input file: %w", a.job.file, a.outputPath, ErrInvalidJob)
+		}
+
+		a.entries = a.job.entries()
+	}
+
+	// parts of the media files (e.g. 'intro.mp3@0:05-')
+	for i, f := range filteredMediaFiles {
+		if !f.cut.isSet() {
+			continue
+		}
+
+		if len(a.entries) != len(a.mediaFiles) {
+			a.entries = make([]entry, len(a.mediaFiles))
+		}
+
+		a.entries[i].cut = f.cut
+		a.statusPrinter.cutFile(f.path, f.cut)
+	}
+
 	a.statusPrinter.listInputFiles(a.mediaFiles, a.outputPath)
 
-	a.coverFile, a.coverFileMimeType, err = lookupMimeType(getDiscoverableFile(a.fs, a.coverFile, a.noDiscovery, "cover", isAcceptedCoverFile, coverFiles))
+	discoveryOrder, err := parseDiscoveryOrder(a.discoveryOrder)
+	if err != nil {
+		return err
+	}
+
+	d := discovery{
+		fs:       a.fs,
+		disabled: a.noDiscovery,
+		dirs:     discoveryDirs(a.fs, discoveryOrder, a.mediaFiles, a.outputPath),
+		names:    a.discoveryNames,
+		observer: a.statusPrinter.discoveredFile,
+	}
+
+	pictureFiles, err := parsePictures(a.pictureArgs)
 	if err != nil {
 		return err
 	}
 
+	if front, ok := pictureFiles[pictureFront]; ok {
+		if a.coverFile != "" {
+			return fmt.Errorf("the front cover is set with '--%s' and '--%s': %w", flagCover, flagPicture, ErrInvalidPicture)
+		}
+
+		a.coverFile = front
+	}
+
+	a.coverFile, a.coverFileMimeType, err = lookupMimeType(a.fs)(d.file(a.coverFile, "cover", d.names.isAcceptedCoverFile, d.names.coverFiles()))
+	if err != nil {
+		return err
+	}
+
+	if a.coverMaxSize < 0 {
+		return fmt.Errorf("%s: '%d' must not be negative: %w", flagCoverMaxSize, a.coverMaxSize, ErrInvalidCoverOption)
+	}
+
+	a.coverFormat, err = cover.ParseFormat(a.coverFormat)
+	if err != nil {
+		return fmt.Errorf("%s: %v: %w", flagCoverFormat, err, ErrInvalidCoverOption)
+	}
+
 	a.statusPrinter.coverFile(a.coverFile)
 
-	a.interlaceFile, err = getDiscoverableFile(a.fs, a.interlaceFile, a.noDiscovery, "interlace", isAcceptedInterlaceFile, interlaceFiles)
+	if a.extractCover != "" {
+		a.extractCover, err = getExtractCoverFile(a.fs, a.extractCover, a.overwrite)
+		if err != nil {
+			return err
+		}
+	}
+
+	a.pictures, err = getPictures(a.fs, d, pictureFiles)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range a.pictures {
+		a.statusPrinter.pictureFile(p.kind.description, p.file)
+	}
+
+	a.interlaceFile, err = d.file(a.interlaceFile, "interlace", isAcceptedInterlaceFile, d.names.interlaceFiles())
 	if err != nil {
 		return err
 	}
 
 	a.statusPrinter.interlaceFile(a.interlaceFile)
 
+	if a.gap < 0 {
+		return fmt.Errorf("%s: '%s' must not be negative: %w", flagGap, a.gap, ErrInvalidGap)
+	}
+
+	if a.gap > 0 {
+		a.statusPrinter.gap(a.gap)
+	}
+
+	if a.trimSilence && a.trimThreshold >= 0 {
+		return fmt.Errorf("%s: '%g' must be negative (dB): %w", flagTrimThreshold, a.trimThreshold, ErrInvalidThreshold)
+	}
+
+	for _, s := range []struct {
+		kind string
+		file *string
+	}{
+		{kind: "folder spacer", file: &a.folderSpacer},
+		{kind: "intro", file: &a.introFile},
+		{kind: "outro", file: &a.outroFile},
+	} {
+		if *s.file == "" {
+			continue
+		}
+
+		*s.file, err = d.file(*s.file, s.kind, isAcceptedInterlaceFile, nil)
+		if err != nil {
+			return err
+		}
+
+		a.statusPrinter.spacerFile(s.kind, *s.file)
+	}
+
+	// interlace files of single entries (e.g. from a job file)
+	for i := range a.entries {
+		if interlace := a.entries[i].interlace; interlace != nil && *interlace != "" {
+			file, err := d.file(*interlace, "interlace", isAcceptedInterlaceFile, nil)
+			if err != nil {
+				return err
+			}
+
+			a.entries[i].interlace = &file
+		}
+	}
+
+	a.interlaceMismatch, err = parseInterlaceMismatch(a.interlaceMismatch)
+	if err != nil {
+		return err
+	}
+
+	if err := a.checkInterlaceFiles(); err != nil {
+		return err
+	}
+
+	a.onCorrupt, err = parseOnCorrupt(a.onCorrupt)
+	if err != nil {
+		return err
+	}
+
+	if a.copyTagsFromIndex > 0 && a.mergeTags != "" {
+		return fmt.Errorf("the tags are copied with '--%s' and merged with '--%s': %w", flagCopyTags, flagMergeTags, ErrInvalidStrategy)
+	}
+
 	if a.copyTagsFromIndex > 0 {
 		if a.copyTagsFromIndex-1 >= len(a.mediaFiles) {
 			return fmt.Errorf("index: '%d': %w", a.copyTagsFromIndex, ErrInvalidIndex)
@@ -90,92 +277,312 @@ func (a *application) args(c *cobra.Command, args []string) error {
 		a.statusPrinter.copyTagsFrom(a.mediaFiles[a.copyTagsFromIndex-1])
 	}
 
-	if a.applyTags != "" {
-		tags, err := keyvalue.StringAsStringMap(a.applyTags)
+	if a.mergeTags != "" {
+		a.mergeStrategies, err = getMergeStrategies(a.mergeTags)
 		if err != nil {
 			return err
 		}
 
-		for k, v := range tags {
-			if v == "" {
-				delete(a.tags, k)
-				// keep the empty value in a.tags to remove the tag again when e.g. copying from an input file
+		a.statusPrinter.mergeTags(a.mergeStrategies)
+	}
+
+	// The tags of a later source replace the tags of an earlier one: the tags set by the configs,
+	// the tags of the job and the tags set on the command line.
+	var fromConfigs, fromCommandLine []func() (map[string]string, error)
+	for _, s := range []struct {
+		flag  string
+		value string
+		read  func() (map[string]string, error)
+	}{
+		{flag: flagTagsFile, value: a.tagsFile, read: func() (map[string]string, error) { return getTagsFromFile(a.fs, a.tagsFile) }},
+		{flag: flagApplyTags, value: a.applyTags, read: func() (map[string]string, error) { return keyvalue.StringAsStringMap(a.applyTags) }},
+	} {
+		switch {
+		case s.value == "":
+			continue
+		case explicit[s.flag]:
+			fromCommandLine = append(fromCommandLine, s.read)
+		default:
+			fromConfigs = append(fromConfigs, s.read)
+		}
+	}
+
+	tagSources := fromConfigs
+	if a.job != nil {
+		tagSources = append(tagSources, a.job.tags)
+	}
+	tagSources = append(tagSources, fromCommandLine...)
+
+	for _, read := range tagSources {
+		tags, err := read()
+		if err != nil {
+			return err
+		}
+
+		if len(tags) == 0 {
+			continue
+		}
+
+		tags, err = a.addTags(tags)
+		if err != nil {
+			return err
+		}
+
+		a.statusPrinter.tagsToApply(tags, a.tagResolver)
+	}
+
+	return nil
+}
+
+// addTags adds the tags to apply to the output file. An empty value removes the tag.
+// The keys are normalized (e.g. 'COMM[eng:description]' to 'COMM:eng:description') and long
+// texts (e.g. lyrics) starting with '@' are read from a file. The values are normalized and
+// validated by the rules of their frame, which rejects invalid values in strict mode only.
+// The added tags are returned.
+func (a *application) addTags(values map[string]string) (map[string]string, error) {
+	added := make(map[string]string, len(values))
+
+	for k, v := range values {
+		key := tags.ParseKey(k)
+		k = key.String()
+
+		if key.IsLongText() && strings.HasPrefix(v, fileReferencePrefix) {
+			content, err := readTextFile(a.fs, strings.TrimPrefix(v, fileReferencePrefix))
+			if err != nil {
+				return nil, fmt.Errorf("tag '%s': %w", k, err)
+			}
+
+			v = content
+		}
+
+		v = a.tagResolver.Normalize(k, v)
+
+		if err := a.tagResolver.Validate(k, v); err != nil {
+			if a.strict || !errors.Is(err, ErrTagInvalidValue) {
+				return nil, err
+			}
+
+			fmt.Fprintf(a.status, "! Warning: %v, but will be written\n", err)
+		}
+
+		if v == "" {
+			delete(a.tags, k)
+			// keep the empty value in a.tags to remove the tag again when e.g. copying from an input file
+		}
+
+		if _, err := a.tagResolver.DescriptionFor(key.ID); err != nil {
+			if a.strict {
+				return nil, fmt.Errorf("tag '%s': %w", k, err)
 			}
 
 			if !a.verbose {
-				if _, err := a.tagResolver.DescriptionFor(k); err != nil {
-					fmt.Fprintf(a.status, "! Warning: the tag '%s' is not a well-known tag, but will be written\n", k)
-				}
+				fmt.Fprintf(a.status, "! Warning: the tag '%s' is not a well-known tag, but will be written\n", k)
 			}
+		}
+
+		a.tags[k] = v
+		added[k] = v
+	}
+
+	return added, nil
+}
+
+// readTextFile reads the content of a text file (e.g. lyrics) referenced by a tag.
+func readTextFile(fs aferox.Aferox, name string) (string, error) {
+	abs := fs.Abs(name)
 
-			a.tags[k] = v
+	content, err := afero.ReadFile(fs, abs)
+	if err != nil {
+		if errors.Is(err, fs2.ErrNotExist) {
+			return "", fmt.Errorf("'%s': %w", abs, ErrFileNotFound)
 		}
 
-		a.statusPrinter.tagsToApply(tags, a.tagResolver)
+		return "", err
 	}
 
-	return nil
+	return strings.TrimRight(string(content), "\r\n"), nil
 }
 
-// isAcceptedMediaFile indicates if a file is accepted for joining.
-func isAcceptedMediaFile(path string, skipInterlaceFiles bool) bool {
-	// ignore the magic interlace files
-	if skipInterlaceFiles && slice.Contains(interlaceFiles, strings.ToLower(filepath.Base(path))) {
-		return false
+// getTagsFromFile reads the tags from a sidecar file (e.g. JSON, YAML or 'KEY=value' per line).
+// Multiple values for a tag are joined.
+func getTagsFromFile(fs aferox.Aferox, tagsFile string) (map[string]string, error) {
+	abs := fs.Abs(tagsFile)
+
+	f, err := fs.Open(abs)
+	if err != nil {
+		if errors.Is(err, fs2.ErrNotExist) {
+			return nil, fmt.Errorf("tags file: '%s': %w", abs, ErrFileNotFound)
+		}
+
+		return nil, err
 	}
+	defer f.Close()
 
-	return slice.Contains(mediaFileExtensions, strings.ToLower(filepath.Ext(path)))
+	values, err := tagfile.Decode(abs, f)
+	if err != nil {
+		return nil, fmt.Errorf("tags file '%s': %w", abs, err)
+	}
+
+	return joinTagValues(values), nil
 }
 
-// getInputFileAsList takes the content of an input file provides it as a list. An empty
-// file is treated as an error.
-func getInputFileAsList(fs aferox.Aferox, inputFile string) ([]string, error) {
+// joinTagValues joins multiple values of a tag, long texts (e.g. comments) line by line.
+func joinTagValues(values map[string][]string) map[string]string {
+	joined := make(map[string]string, len(values))
+	for k, v := range values {
+		separator := multipleValuesSeparator
+		if tags.ParseKey(k).IsLongText() {
+			separator = multipleLinesSeparator
+		}
+
+		joined[k] = strings.Join(v, separator)
+	}
+
+	return joined
+}
+
+// getMergeStrategies parses the merge strategy for each tag (e.g. 'TALB=common,TCOM=concat').
+func getMergeStrategies(input string) (map[string]mp3binder.MergeStrategy, error) {
+	pairs, err := keyvalue.StringAsStringMap(input)
+	if err != nil {
+		return nil, err
+	}
+
+	strategies := make(map[string]mp3binder.MergeStrategy, len(pairs))
+	for id, name := range pairs {
+		strategy, ok := mergeStrategies[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("tag '%s' with strategy '%s': %w", id, name, ErrInvalidStrategy)
+		}
+
+		strategies[id] = strategy
+	}
+
+	return strategies, nil
+}
+
+// getInputFileAsList takes the content of an input file (a list or a M3U, M3U8 or PLS playlist)
+// and provides it as a list. Relative paths are relative to the input file, glob patterns are
+// expanded unless a file of the name exists. The titles of a playlist are provided by the path. An empty file is treated as an error.
+func getInputFileAsList(fs aferox.Aferox, inputFile string) ([]string, map[string]string, error) {
 	abs := fs.Abs(inputFile)
 	exists, err := fs.Exists(abs)
 	switch {
 	case err != nil:
-		return nil, err
+		return nil, nil, err
 	case !exists:
-		return nil, fmt.Errorf("'%s': %w", abs, ErrFileNotFound)
+		return nil, nil, fmt.Errorf("'%s': %w", abs, ErrFileNotFound)
 	}
 
 	isDir, err := fs.IsDir(abs)
 	switch {
 	case err != nil:
-		return nil, err
+		return nil, nil, err
 	case isDir:
-		return nil, fmt.Errorf("file is a directory '%s': %w", abs, ErrInvalidFile)
+		return nil, nil, fmt.Errorf("file is a directory '%s': %w", abs, ErrInvalidFile)
 	}
 
 	isEmpty, err := fs.IsEmpty(abs)
 	switch {
 	case err != nil:
-		return nil, err
+		return nil, nil, err
 	case isEmpty:
-		return nil, fmt.Errorf("file is empty '%s': %w", abs, ErrInvalidFile)
+		return nil, nil, fmt.Errorf("file is empty '%s': %w", abs, ErrInvalidFile)
 	}
 
-	// File content as simple string array
-	f, err := fs.Open(inputFile)
+	f, err := fs.Open(abs)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer f.Close()
 
-	s := bufio.NewScanner(f)
-	s.Split(bufio.ScanLines)
+	entries, err := playlist.Decode(abs, f)
+	if err != nil {
+		return nil, nil, fmt.Errorf("input file '%s': %v: %w", abs, err, ErrInvalidFile)
+	}
+
 	args := []string{}
+	titles := make(map[string]string)
 
-	for s.Scan() {
-		args = append(args, s.Text())
+	for _, e := range entries {
+		path := e.Path
+		// playlists of media players may contain file URLs
+		if u, err := url.Parse(path); err == nil && u.Scheme == fileURLScheme {
+			path = u.Path
+		}
+
+		// the folder of the input file is not part of the pattern (e.g. 'Book [1]')
+		isPattern := isGlobPattern(path)
+		pattern := path
+		if !filepath.IsAbs(path) {
+			pattern = filepath.Join(escapeGlobPattern(filepath.Dir(abs)), path)
+			path = filepath.Join(filepath.Dir(abs), path)
+		}
+
+		// the file without the time range (e.g. 'intro.mp3@0:05-'), the range is checked later
+		file, _, err := splitTimeRange(fs, path)
+		if err != nil {
+			file = path
+		}
+
+		// an existing file is taken as it is (e.g. '01 [intro].mp3')
+		exists, err := fs.Exists(file)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if exists || !isPattern {
+			args = append(args, path)
+			if e.Title != "" {
+				// the title belongs to the file without the time range
+				titles[filepath.Clean(file)] = e.Title
+			}
+
+			continue
+		}
+
+		matches, err := afero.Glob(fs, pattern)
+		if err != nil {
+			return nil, nil, fmt.Errorf("input file '%s': pattern '%s': %v: %w", abs, e.Path, err, ErrInvalidFile)
+		}
+
+		if len(matches) == 0 {
+			return nil, nil, fmt.Errorf("input file '%s': pattern '%s' matches no files: %w", abs, e.Path, ErrFileNotFound)
+		}
+
+		sort.Strings(matches)
+		args = append(args, matches...)
+	}
+
+	return args, titles, nil
+}
+
+// isGlobPattern returns true if the path contains any of the special characters of a glob pattern.
+func isGlobPattern(path string) bool {
+	return strings.ContainsAny(path, globCharacters)
+}
+
+// escapeGlobPattern escapes the special characters of a glob pattern in a path, so that the path
+// only matches itself.
+func escapeGlobPattern(path string) string {
+	var b strings.Builder
+	for _, r := range path {
+		switch {
+		case strings.ContainsRune(globCharacters, r):
+			b.WriteString("[" + string(r) + "]")
+		case r == '\\' && filepath.Separator != '\\':
+			b.WriteString(`\\`)
+		default:
+			b.WriteRune(r)
+		}
 	}
 
-	return args, nil
+	return b.String()
 }
 
 // getMediaFilesFromArguments takes the program arguments and either accepts the argument as a file or if the argument
 // is a directory, accepts the files contained in the directory.
-func getMediaFilesFromArguments(fs aferox.Aferox, args []string) ([]mediaFile, string, error) {
+func getMediaFilesFromArguments(fs aferox.Aferox, names discoveryNames, args []string) ([]mediaFile, string, error) {
 	var files []mediaFile
 	var outputFileCandidate string
 
@@ -186,7 +593,7 @@ func getMediaFilesFromArguments(fs aferox.Aferox, args []string) ([]mediaFile, s
 	}
 
 	for _, arg := range args {
-		filesFromParameter, candidate, err := getMediaFilesFromArgument(fs, arg)
+		filesFromParameter, candidate, err := getMediaFilesFromArgument(fs, names, arg)
 		if err != nil {
 			if errors.Is(err, fs2.ErrNotExist) {
 				return nil, "", fmt.Errorf("file: '%s': %w", arg, ErrFileNotFound)
@@ -206,8 +613,11 @@ func getMediaFilesFromArguments(fs aferox.Aferox, args []string) ([]mediaFile, s
 
 // getMediaFilesFromArgument takes a program argument and either accepts the argument as a file or if the argument
 // is a directory, accepts the files contained in the directory.
-func getMediaFilesFromArgument(fs aferox.Aferox, arg string) ([]mediaFile, string, error) {
-	arg = fs.Abs(arg)
+func getMediaFilesFromArgument(fs aferox.Aferox, names discoveryNames, arg string) ([]mediaFile, string, error) {
+	arg, cut, err := splitTimeRange(fs, fs.Abs(arg))
+	if err != nil {
+		return nil, "", err
+	}
 
 	info, err := fs.Stat(arg)
 	if err != nil {
@@ -216,13 +626,17 @@ func getMediaFilesFromArgument(fs aferox.Aferox, arg string) ([]mediaFile, strin
 
 	// regular file
 	if !info.IsDir() {
-		if isAcceptedMediaFile(arg, false) {
-			return []mediaFile{{path: arg, explicitlySet: true}}, filepath.Base(filepath.Dir(arg)), nil
+		if names.isAcceptedMediaFile(arg, false) {
+			return []mediaFile{{path: arg, explicitlySet: true, cut: cut}}, filepath.Base(filepath.Dir(arg)), nil
 		}
 
 		return nil, "", fmt.Errorf("media file '%s': %w", info.Name(), ErrInvalidFile)
 	}
 
+	if cut.isSet() {
+		return nil, "", fmt.Errorf("directory '%s': only files can be cut: %w", arg, ErrInvalidRange)
+	}
+
 	// special case for root directories (e.g. removable media)
 	candidateName := value.OrDefaultStr(info.Name(), rootDirectoryName)
 
@@ -239,7 +653,7 @@ func getMediaFilesFromArgument(fs aferox.Aferox, arg string) ([]mediaFile, strin
 		}
 
 		abs := fs.Abs(filepath.Join(arg, file.Name()))
-		if !isAcceptedMediaFile(abs, true) {
+		if !names.isAcceptedMediaFile(abs, true) {
 			continue
 		}
 
@@ -249,68 +663,56 @@ func getMediaFilesFromArgument(fs aferox.Aferox, arg string) ([]mediaFile, strin
 	return files, candidateName, nil
 }
 
-// isAcceptedCoverFile returns true if the provided path points to a valid cover file.
-func isAcceptedCoverFile(path string) bool {
-	return slice.Contains(coverFileExtensions, strings.ToLower(filepath.Ext(path)))
-}
-
 // isAcceptedCoverFile returns true if the provided path points to a valid interlace file.
 func isAcceptedInterlaceFile(path string) bool {
 	return slice.Contains(mediaFileExtensions, strings.ToLower(filepath.Ext(path)))
 }
 
 // lookupMimeType accepts the returns of a 'filename/error' function and
-// annotates the result with the mime type of the 'filename'.
-func lookupMimeType(name string, err error) (string, string, error) {
-	if err != nil {
-		return name, "", err
-	}
-
-	return name, getMimeTypeOfImageByExtension(filepath.Ext(name)), nil
-}
-
-func getDiscoverableFile(fs aferox.Aferox, file string, noDiscovery bool, fileType string, accept func(string) bool, wellKnownFiles []string) (string, error) {
-	// not set and no discovery
-	if file == "" && noDiscovery {
-		return "", nil
-	}
-
-	// explicitly set
-	if file != "" {
-		file = fs.Abs(file)
-
-		info, err := fs.Stat(file)
-		if err != nil {
-			if errors.Is(err, fs2.ErrNotExist) {
-				return "", fmt.Errorf("%s file: '%s': %w", fileType, file, ErrFileNotFound)
-			}
-
-			return "", err
+// annotates the result with the mime type detected from the content of the 'filename'.
+func lookupMimeType(fs aferox.Aferox) func(name string, err error) (string, string, error) {
+	return func(name string, err error) (string, string, error) {
+		if err != nil || name == "" {
+			return name, "", err
 		}
 
-		if info.IsDir() {
-			return "", ErrInvalidFile
+		f, err := fs.Open(name)
+		if err != nil {
+			return name, "", err
 		}
+		defer f.Close()
 
-		if !accept(file) {
-			return "", fmt.Errorf("%s file '%s': %w", fileType, info.Name(), ErrInvalidFile)
+		mimeType, err := cover.DetectMimeType(f)
+		if err != nil {
+			return name, "", fmt.Errorf("image file: '%s': %v: %w", name, err, ErrInvalidFile)
 		}
 
-		return file, nil
+		return name, mimeType, nil
 	}
+}
+
+// getExtractCoverFile returns the path to save the cover to. An existing file is only overwritten if requested.
+func getExtractCoverFile(fs aferox.Aferox, file string, overwrite bool) (string, error) {
+	file = fs.Abs(file)
 
-	// discover
-	dir := fs.Abs(fs.Getwd())
-	dirListing, err := fs.ReadDir(dir)
+	info, err := fs.Stat(file)
 	if err != nil {
+		if errors.Is(err, fs2.ErrNotExist) {
+			return file, nil
+		}
+
 		return "", err
 	}
 
-	if found := slice.FirstEqual(slice.Map(dirListing, func(info fs2.FileInfo) string { return info.Name() }), wellKnownFiles, strings.ToLower); found != nil {
-		return filepath.Join(dir, *found), nil
+	if info.IsDir() {
+		return "", fmt.Errorf("%s: '%s' is a directory: %w", flagExtractCover, file, ErrInvalidFile)
+	}
+
+	if !overwrite {
+		return "", fmt.Errorf("%s: '%s': %w", flagExtractCover, file, ErrOutputFileExists)
 	}
 
-	return "", nil
+	return file, nil
 }
 
 func getOutputFile(fs aferox.Aferox, outputPath string, overwrite bool, candidate string) (string, error) {
@@ -383,9 +785,9 @@ func mediaFilePartitionStr(m mediaFile) string {
 
 // filterMediaFiles performs various filters (e.g. removing duplicates when the sources:
 // directory, command line arguments are mixed).
-func filterMediaFiles(files []mediaFile, outputFile string) []string {
+func filterMediaFiles(files []mediaFile, outputFile string) []mediaFile {
 	if len(files) == 0 {
-		return []string{}
+		return []mediaFile{}
 	}
 
 	seenFiles := make(map[string]struct{})
@@ -418,7 +820,7 @@ func filterMediaFiles(files []mediaFile, outputFile string) []string {
 
 	// if there is no partition, just return the files
 	if len(explicitlySet) == 0 || len(discovered) == 0 {
-		return slice.Map(preparedFiles, slice.String[mediaFile])
+		return preparedFiles
 	}
 
 	// remove the explicitly set files from the discovered files
@@ -429,16 +831,5 @@ func filterMediaFiles(files []mediaFile, outputFile string) []string {
 		return orderedFiles[p].OriginalIndex < orderedFiles[q].OriginalIndex
 	})
 
-	return slice.Map(orderedFiles, slice.String[slice.PartitionResult[mediaFile]])
-}
-
-func getMimeTypeOfImageByExtension(ext string) string {
-	switch strings.ToLower(ext) {
-	default:
-		fallthrough
-	case ".jpg", ".jpeg":
-		return "image/jpeg"
-	case ".png":
-		return "image/png"
-	}
+	return slice.Map(orderedFiles, func(r slice.PartitionResult[mediaFile]) mediaFile { return r.Element })
 }
@@ -60,6 +60,14 @@ func (t *testTagResolver) DescriptionFor(string) (string, error) {
 	return "", nil
 }
 
+func (t *testTagResolver) Validate(string, string) error {
+	return nil
+}
+
+func (t *testTagResolver) Normalize(_, value string) string {
+	return value
+}
+
 func newDefaultApplication(fs aferox.Aferox) *application {
 	return &application{
 		fs:            fs,
@@ -352,3 +360,39 @@ func TestFilesAndDirectoryUniqueButKeepDuplicatesFromArg(t *testing.T) {
 		assert.Equal(t, filepathJoin(root, validFileName1, validFileName1, validFileName2, validFileName2), a.mediaFiles)
 	}
 }
+
+func TestCommandFlags(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []struct {
+		command  string
+		flags    []string
+		accepted bool
+	}{
+		{command: "run", flags: []string{"--" + flagGap, "2s", "--" + flagOutputFile, "book.mp3"}, accepted: true},
+		{command: "run", flags: []string{"--" + flagVerbose}, accepted: true},
+		{command: "inspect", flags: []string{"--" + flagVerbose, "--" + flagJSON}, accepted: true},
+		{command: "inspect", flags: []string{"--" + flagGap, "2s"}},
+		{command: "verify", flags: []string{"--" + flagVerbose}, accepted: true},
+		{command: "verify", flags: []string{"--" + flagOutputFile, "book.mp3"}},
+	} {
+		f := f // pin
+		t.Run(f.command+" "+strings.Join(f.flags, " "), func(t *testing.T) {
+			t.Parallel()
+			root, fs := newTestFilesystem()
+
+			a := newConfiguredApplication(fs, root)
+			cmd, _, err := a.command.Find([]string{f.command})
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			err = cmd.ParseFlags(f.flags)
+			if f.accepted {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, "unknown flag")
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carolynvs/aferox"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+func TestInvalidChapterTitleTemplate(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = withTwoValidFiles(fs, root)
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+	a.chapterTitle = "{{.index"
+
+	err := a.args(nil, []string{"."})
+	assert.ErrorIs(t, err, ErrInvalidTemplate)
+}
+
+func TestValidChapterTitleTemplate(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = withTwoValidFiles(fs, root)
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+	a.chapterTitle = "{{.index}}. {{.TIT2}}"
+
+	err := a.args(nil, []string{"."})
+	if assert.NoError(t, err) {
+		assert.NotNil(t, a.chapterTemplate)
+	}
+}
+
+func TestRenderChapterTitle(t *testing.T) {
+	t.Parallel()
+	tags := map[string]string{"TIT2": "The Title", "TPE1": "The Artist"}
+
+	for _, f := range []struct {
+		title    string
+		template string
+		file     string
+		expected string
+	}{
+		{title: "Tags and index", template: "{{.index}}. {{.TIT2}} – {{.TPE1}}", file: "01.mp3", expected: "3. The Title – The Artist"},
+		{title: "Missing tag", template: "{{.TALB}}", file: "01.mp3", expected: ""},
+		{title: "Default for missing tag", template: "{{.TALB | default .name}}", file: "01 - Intro.mp3", expected: "01 - Intro"},
+		{title: "Filename", template: "{{.filename}}", file: "/some/01 - Intro.mp3", expected: "01 - Intro.mp3"},
+		{title: "Without track number", template: "{{.name | notrack}}", file: "01 - Intro.mp3", expected: "Intro"},
+		{title: "Without disc and track number", template: "{{.name | notrack}}", file: "1-02. Intro.mp3", expected: "Intro"},
+		{title: "Only track number", template: "{{.name | notrack}}", file: "01.mp3", expected: "01"},
+		{title: "Title cased", template: "{{.name | notrack | replace \"_\" \" \" | title}}", file: "03_the_end.mp3", expected: "The End"},
+		{title: "Duration", template: "{{.duration}}", file: "01.mp3", expected: "1:02:03"},
+	} {
+		f := f // pin
+		t.Run(f.title, func(t *testing.T) {
+			t.Parallel()
+
+			tmpl, err := parseChapterTitleTemplate(language.English, f.template)
+			if assert.NoError(t, err) {
+				duration := time.Hour + 2*time.Minute + 3*time.Second
+				title, err := renderChapterTitle(tmpl, tags, f.file, 3, duration)
+				if assert.NoError(t, err) {
+					assert.Equal(t, f.expected, title)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/carolynvs/aferox"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+const testUserConfigFile = "/config/mp3binder/config.yaml"
+
+func newConfiguredApplication(fs afero.Fs, root string, flags ...string) *application {
+	a := New(context.Background(), "", "test", "", &bytes.Buffer{}, fs, root, nil, &testTagResolver{}, supportedLanguage, testUserConfigFile).(*application)
+	if err := a.command.ParseFlags(flags); err != nil {
+		panic(err)
+	}
+
+	return a
+}
+
+func writeConfig(fs afero.Fs, file, content string) {
+	if err := afero.WriteFile(fs, file, []byte(content), 0o644); err != nil {
+		panic(err)
+	}
+}
+
+func TestConfigPrecedence(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = withTwoValidFiles(fs, root)
+	writeConfig(fs, testUserConfigFile, "cover-max-size: 100\ncover-format: png\npicture: [back=user.png]\n")
+	writeConfig(fs, filepath.Join(root, folderConfigFileName), "cover-max-size: 200\npicture:\n  - artist=folder.png\n")
+	_ = makeImageFiles(fs, root, "user.png", "folder.png", "flag.png")
+
+	for _, f := range []struct {
+		title           string
+		flags           []string
+		expectedSize    int
+		expectedPicture string
+	}{
+		{title: "Folder config over user config", expectedSize: 200, expectedPicture: "artist=folder.png"},
+		{title: "Flags over folder config", flags: []string{"--cover-max-size", "300", "--picture", "back=flag.png"}, expectedSize: 300, expectedPicture: "back=flag.png"},
+	} {
+		f := f // pin
+		t.Run(f.title, func(t *testing.T) {
+			t.Parallel()
+
+			a := newConfiguredApplication(fs, root, f.flags...)
+
+			err := a.args(a.command, []string{"."})
+			if assert.NoError(t, err) {
+				assert.Equal(t, f.expectedSize, a.coverMaxSize)
+				// set by the user config only
+				assert.Equal(t, "png", a.coverFormat)
+				assert.Equal(t, []string{f.expectedPicture}, a.pictureArgs)
+			}
+		})
+	}
+}
+
+func TestConfigInFolderOfFirstArgument(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	dir := filepath.Join(root, "album")
+	_ = withTwoValidFiles(fs, dir)
+	writeConfig(fs, filepath.Join(dir, folderConfigFileName), "nochapters: true\n")
+
+	a := newConfiguredApplication(fs, root)
+
+	err := a.args(a.command, []string{filepath.Join("album", validFileName1), filepath.Join("album", validFileName2)})
+	if assert.NoError(t, err) {
+		assert.True(t, a.noChapters)
+	}
+}
+
+func TestConfigExtendsDiscovery(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = withTwoValidFiles(fs, root)
+	writeConfig(fs, filepath.Join(root, folderConfigFileName), "discovery:\n  cover: [Artwork]\n  cover-extensions: [tif]\n  interlace: [silence.mp3]\n  pictures:\n    back: [rueckseite]\n")
+	coverFiles := makeImageFiles(fs, root, "artwork.tif", "rueckseite.png")
+	interlaceFiles := makeEmptyFiles(fs, root, "silence.mp3")
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+
+	err := a.args(nil, []string{"."})
+	if assert.NoError(t, err) && assert.Len(t, a.pictures, 1) {
+		assert.Equal(t, coverFiles[0], a.coverFile)
+		assert.Equal(t, coverFiles[1], a.pictures[0].file)
+		assert.Equal(t, interlaceFiles[0], a.interlaceFile)
+		// the interlace file is not an input file
+		assert.Len(t, a.mediaFiles, 2)
+	}
+}
+
+func TestInvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []struct {
+		title   string
+		content string
+	}{
+		{title: "Unknown option", content: "colour: true\n"},
+		{title: "Excluded option", content: "help: true\n"},
+		{title: "Multiple values", content: "cover-max-size: [1, 2]\n"},
+		{title: "Invalid value", content: "cover-max-size: large\n"},
+		{title: "Nested value", content: "cover:\n  file: cover.jpg\n"},
+		{title: "Unknown picture type", content: "discovery:\n  pictures:\n    side: [side]\n"},
+		{title: "Invalid YAML", content: "cover: [\n"},
+	} {
+		f := f // pin
+		t.Run(f.title, func(t *testing.T) {
+			t.Parallel()
+			root, fs := newTestFilesystem()
+			_ = withTwoValidFiles(fs, root)
+			writeConfig(fs, testUserConfigFile, f.content)
+
+			a := newConfiguredApplication(fs, root)
+
+			err := a.args(a.command, []string{"."})
+			assert.ErrorIs(t, err, ErrInvalidConfig)
+		})
+	}
+}
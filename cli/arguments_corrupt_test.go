@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/carolynvs/aferox"
+	"github.com/crra/mp3binder/mp3binder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOnCorrupt(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []struct {
+		input    string
+		expected string
+		err      error
+	}{
+		{input: "", expected: defaultOnCorrupt},
+		{input: corruptSkip, expected: corruptSkip},
+		{input: " Abort ", expected: corruptAbort},
+		{input: corruptWarn, expected: corruptWarn},
+		{input: "ignore", err: ErrInvalidOnCorrupt},
+	} {
+		f := f // pin
+		t.Run(f.input, func(t *testing.T) {
+			t.Parallel()
+
+			onCorrupt, err := parseOnCorrupt(f.input)
+			if f.err != nil {
+				assert.ErrorIs(t, err, f.err)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, f.expected, onCorrupt)
+			}
+		})
+	}
+}
+
+func TestInvalidOnCorrupt(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = withTwoValidFiles(fs, root)
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+	a.onCorrupt = "ignore"
+
+	err := a.args(nil, []string{"."})
+	assert.ErrorIs(t, err, ErrInvalidOnCorrupt)
+}
+
+func TestCorruptionFound(t *testing.T) {
+	t.Parallel()
+
+	corruptions := []mp3binder.Corruption{
+		{Offset: 8192, Length: 417, Position: 1500 * time.Millisecond},
+		{Offset: 66820, Length: 3, Position: 4 * time.Second},
+	}
+
+	for _, f := range []struct {
+		onCorrupt string
+		warning   bool
+		err       error
+	}{
+		{onCorrupt: corruptSkip},
+		{onCorrupt: corruptWarn, warning: true},
+		{onCorrupt: corruptAbort, err: ErrCorruptInput},
+	} {
+		f := f // pin
+		t.Run(f.onCorrupt, func(t *testing.T) {
+			t.Parallel()
+
+			status := &bytes.Buffer{}
+			a := &application{status: status, onCorrupt: f.onCorrupt, mediaFiles: []string{"01.mp3", "02.mp3"}}
+
+			err := a.corruptionFound(1, corruptions)
+			if f.err != nil {
+				assert.ErrorIs(t, err, f.err)
+				assert.Contains(t, err.Error(), "'02.mp3' has 2 corrupt region(s) of 420 bytes")
+				return
+			}
+
+			if assert.NoError(t, err) && f.warning {
+				assert.Contains(t, status.String(), "417 bytes at byte 8192 (0:02), 3 bytes at byte 66820 (0:04)")
+			} else {
+				assert.Empty(t, status.String())
+			}
+		})
+	}
+}
+
+func TestListCorruptions(t *testing.T) {
+	t.Parallel()
+
+	corruptions := make([]mp3binder.Corruption, maxListedCorruptions+2)
+	for i := range corruptions {
+		corruptions[i] = mp3binder.Corruption{Offset: int64(i * 1000), Length: 10, Position: time.Duration(i) * time.Minute}
+	}
+
+	list := listCorruptions(corruptions)
+	assert.Contains(t, list, "10 bytes at byte 4000 (4:00)")
+	assert.NotContains(t, list, "at byte 5000")
+	assert.True(t, strings.HasSuffix(list, ", and 2 more"))
+}
+
+func TestCRCMismatchFound(t *testing.T) {
+	t.Parallel()
+
+	positions := []time.Duration{261224490, 1306122448}
+
+	for _, f := range []struct {
+		dropBadFrames bool
+		expected      string
+	}{
+		{dropBadFrames: false, expected: "'02.mp3' has 2 frame(s) with a wrong CRC at: 261ms, 1.306s. The frames are kept\n"},
+		{dropBadFrames: true, expected: "'02.mp3' has 2 frame(s) with a wrong CRC at: 261ms, 1.306s. The frames are dropped\n"},
+	} {
+		f := f // pin
+		t.Run(f.expected, func(t *testing.T) {
+			t.Parallel()
+
+			status := &bytes.Buffer{}
+			a := &application{status: status, dropBadFrames: f.dropBadFrames, mediaFiles: []string{"01.mp3", "02.mp3"}}
+
+			a.crcMismatchFound(1, positions)
+			assert.True(t, strings.HasSuffix(status.String(), f.expected))
+		})
+	}
+}
@@ -1,11 +1,20 @@
 package cli
 
 import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/carolynvs/aferox"
+	"github.com/crra/id3v2/v2"
+	"github.com/crra/mp3binder/image/cover"
+	"github.com/crra/mp3binder/mp3binder"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -17,6 +26,84 @@ const (
 	invalidCoverFile = "_"
 )
 
+// the start of the images, which is enough to detect their type
+var (
+	jpegFileContent = []byte("\xFF\xD8\xFF\xE0\x00\x10JFIF\x00")
+	pngFileContent  = []byte("\x89PNG\r\n\x1A\n\x00\x00\x00\x0DIHDR")
+	gifFileContent  = []byte("GIF89a\x01\x00\x01\x00")
+	bmpFileContent  = []byte("BM\x46\x00\x00\x00\x00\x00")
+	webpFileContent = []byte("RIFF\x1A\x00\x00\x00WEBPVP8L")
+)
+
+func makeFiles(fs afero.Fs, path string, content []byte, files ...string) []string {
+	for _, f := range files {
+		afero.WriteFile(fs, filepath.Join(path, f), content, 0o644)
+	}
+
+	return filepathJoin(path, files...)
+}
+
+// withEmbeddedCover prepends a tag with a front cover to a file.
+func withEmbeddedCover(fs afero.Fs, file, mimeType string, picture []byte) {
+	audio, err := afero.ReadFile(fs, file)
+	if err != nil {
+		panic(err)
+	}
+
+	tag := id3v2.NewEmptyTag()
+	tag.AddAttachedPicture(id3v2.PictureFrame{
+		Encoding:    id3v2.EncodingUTF8,
+		MimeType:    mimeType,
+		PictureType: id3v2.PTFrontCover,
+		Picture:     picture,
+	})
+
+	var b bytes.Buffer
+	if _, err := tag.WriteTo(&b); err != nil {
+		panic(err)
+	}
+
+	if err := afero.WriteFile(fs, file, append(b.Bytes(), audio...), 0o644); err != nil {
+		panic(err)
+	}
+}
+
+// validPNG returns a complete PNG image.
+func validPNG() []byte {
+	var b bytes.Buffer
+	if err := png.Encode(&b, image.NewGray(image.Rect(0, 0, 2, 2))); err != nil {
+		panic(err)
+	}
+
+	return b.Bytes()
+}
+
+// frontCoverOfFile returns the front cover of the tag of a file.
+func frontCoverOfFile(fs afero.Fs, file string) (id3v2.PictureFrame, bool) {
+	f, err := fs.Open(file)
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+
+	tag, err := id3v2.ParseReader(f, id3v2.Options{Parse: true})
+	if err != nil {
+		panic(err)
+	}
+
+	for _, frame := range tag.GetFrames(tag.CommonID("Attached picture")) {
+		if pf, ok := frame.(id3v2.PictureFrame); ok && pf.PictureType == id3v2.PTFrontCover {
+			return pf, true
+		}
+	}
+
+	return id3v2.PictureFrame{}, false
+}
+
+func makeImageFiles(fs afero.Fs, path string, files ...string) []string {
+	return makeFiles(fs, path, pngFileContent, files...)
+}
+
 func TestNonExistingCoverFile(t *testing.T) {
 	t.Parallel()
 	root, fs := newTestFilesystem()
@@ -68,7 +155,7 @@ func TestValidCoverFile(t *testing.T) {
 		strings.ToUpper(validCoverFile2),
 		strings.ToUpper(validCoverFile3),
 	} {
-		mediaFile := makeEmptyFiles(fs, root, f)
+		mediaFile := makeImageFiles(fs, root, f)
 
 		a := newDefaultApplication(aferox.NewAferox(root, fs))
 		a.coverFile = mediaFile[0]
@@ -82,7 +169,7 @@ func TestDiscoverCoverFile(t *testing.T) {
 	t.Parallel()
 	root, fs := newTestFilesystem()
 	_ = withTwoValidFiles(fs, root)
-	coverFiles := makeEmptyFiles(fs, root, validCoverFile1)
+	coverFiles := makeImageFiles(fs, root, validCoverFile1)
 
 	a := newDefaultApplication(aferox.NewAferox(root, fs))
 
@@ -111,7 +198,7 @@ func TestDiscoverCoverFileUppercased(t *testing.T) {
 	t.Parallel()
 	root, fs := newTestFilesystem()
 	_ = withTwoValidFiles(fs, root)
-	coverFiles := makeEmptyFiles(fs, root, strings.ToUpper(validCoverFile1))
+	coverFiles := makeImageFiles(fs, root, strings.ToUpper(validCoverFile1))
 
 	a := newDefaultApplication(aferox.NewAferox(root, fs))
 
@@ -120,3 +207,231 @@ func TestDiscoverCoverFileUppercased(t *testing.T) {
 		assert.Equal(t, coverFiles[0], a.coverFile)
 	}
 }
+
+func TestCoverOptions(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = withTwoValidFiles(fs, root)
+
+	for _, f := range []struct {
+		title          string
+		maxSize        int
+		format         string
+		expectedFormat string
+		err            error
+	}{
+		{title: "Defaults"},
+		{title: "Max size", maxSize: 600},
+		{title: "JPEG", format: "jpeg", expectedFormat: cover.FormatJPEG},
+		{title: "JPG uppercased", format: "JPG", expectedFormat: cover.FormatJPEG},
+		{title: "PNG", format: "png", expectedFormat: cover.FormatPNG},
+		{title: "Unsupported format", format: "tiff", err: ErrInvalidCoverOption},
+		{title: "Negative max size", maxSize: -1, err: ErrInvalidCoverOption},
+	} {
+		f := f // pin
+		t.Run(f.title, func(t *testing.T) {
+			t.Parallel()
+
+			a := newDefaultApplication(aferox.NewAferox(root, fs))
+			a.coverMaxSize = f.maxSize
+			a.coverFormat = f.format
+
+			err := a.args(nil, []string{"."})
+			if f.err != nil {
+				assert.ErrorIs(t, err, f.err)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, f.expectedFormat, a.coverFormat)
+			}
+		})
+	}
+}
+
+func TestCoverMimeTypeFromContent(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []struct {
+		name     string
+		content  []byte
+		expected string
+	}{
+		{name: "cover.jpg", content: jpegFileContent, expected: cover.MimeTypeJPEG},
+		{name: "cover.png", content: pngFileContent, expected: cover.MimeTypePNG},
+		{name: "cover.jpg", content: pngFileContent, expected: cover.MimeTypePNG},
+		{name: "cover.png", content: webpFileContent, expected: cover.MimeTypeWebP},
+		{name: "cover.webp", content: webpFileContent, expected: cover.MimeTypeWebP},
+		{name: "cover.gif", content: gifFileContent, expected: cover.MimeTypeGIF},
+		{name: "cover.bmp", content: bmpFileContent, expected: cover.MimeTypeBMP},
+	} {
+		f := f // pin
+		t.Run(f.name+" "+f.expected, func(t *testing.T) {
+			t.Parallel()
+			root, fs := newTestFilesystem()
+			_ = withTwoValidFiles(fs, root)
+			coverFiles := makeFiles(fs, root, f.content, f.name)
+
+			a := newDefaultApplication(aferox.NewAferox(root, fs))
+			a.coverFile = f.name
+
+			err := a.args(nil, []string{"."})
+			if assert.NoError(t, err) {
+				assert.Equal(t, coverFiles[0], a.coverFile)
+				assert.Equal(t, f.expected, a.coverFileMimeType)
+			}
+		})
+	}
+}
+
+func TestCoverFileIsNoImage(t *testing.T) {
+	t.Parallel()
+
+	for _, content := range [][]byte{emptyFileContent, []byte("not an image at all")} {
+		root, fs := newTestFilesystem()
+		_ = withTwoValidFiles(fs, root)
+		_ = makeFiles(fs, root, content, validCoverFile1)
+
+		a := newDefaultApplication(aferox.NewAferox(root, fs))
+
+		err := a.args(nil, []string{"."})
+		assert.ErrorIs(t, err, ErrInvalidFile)
+	}
+}
+
+func TestExtractCover(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = withTwoValidFiles(fs, root)
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+	a.extractCover = "extracted.jpg"
+
+	err := a.args(nil, []string{"."})
+	if assert.NoError(t, err) {
+		assert.Equal(t, filepath.Join(root, "extracted.jpg"), a.extractCover)
+	}
+}
+
+func TestInvalidExtractCover(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = withTwoValidFiles(fs, root)
+	existing := makeImageFiles(fs, root, "existing.jpg")
+	_ = fs.MkdirAll(filepath.Join(root, "folder"), 0o755)
+
+	for _, f := range []struct {
+		title     string
+		file      string
+		overwrite bool
+		err       error
+	}{
+		{title: "Existing file", file: existing[0], err: ErrOutputFileExists},
+		{title: "Folder", file: "folder", overwrite: true, err: ErrInvalidFile},
+	} {
+		f := f // pin
+		t.Run(f.title, func(t *testing.T) {
+			t.Parallel()
+
+			a := newDefaultApplication(aferox.NewAferox(root, fs))
+			a.extractCover = f.file
+			a.overwrite = f.overwrite
+
+			err := a.args(nil, []string{"."})
+			assert.ErrorIs(t, err, f.err)
+		})
+	}
+}
+
+func TestExtractCoverOverwrite(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = withTwoValidFiles(fs, root)
+	existing := makeImageFiles(fs, root, "existing.jpg")
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+	a.extractCover = existing[0]
+	a.overwrite = true
+
+	err := a.args(nil, []string{"."})
+	if assert.NoError(t, err) {
+		assert.Equal(t, existing[0], a.extractCover)
+	}
+}
+
+func TestExtractMissingCover(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = makeMP3Files(fs, root, header44100, 5, validFileName1, validFileName2)
+	status := &bytes.Buffer{}
+
+	a := New(context.Background(), "", "test", "", status, fs, root, mp3binder.New(&testTagResolver{}), &testTagResolver{}, supportedLanguage, testUserConfigFile).(*application)
+	if err := a.command.ParseFlags([]string{"--" + flagExtractCover, "extracted.jpg"}); err != nil {
+		panic(err)
+	}
+
+	err := a.args(a.command, []string{"."})
+	if assert.NoError(t, err) && assert.NoError(t, a.run(a.command, nil)) {
+		// not only with --verbose
+		assert.Contains(t, status.String(), "! Warning: there is no cover to extract to '"+filepath.Join(root, "extracted.jpg")+"'")
+
+		_, err := fs.Stat(filepath.Join(root, "extracted.jpg"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	}
+}
+
+func TestEmbeddedCoverWithWrongMimeType(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	files := makeMP3Files(fs, root, header44100, 5, validFileName1, validFileName2)
+	picture := validPNG()
+	withEmbeddedCover(fs, files[0], cover.MimeTypeJPEG, picture)
+
+	a := New(context.Background(), "", "test", "", &bytes.Buffer{}, fs, root, mp3binder.New(&testTagResolver{}), &testTagResolver{}, supportedLanguage, testUserConfigFile).(*application)
+
+	err := a.args(a.command, []string{"."})
+	if assert.NoError(t, err) && assert.NoError(t, a.run(a.command, nil)) {
+		pf, ok := frontCoverOfFile(fs, a.outputPath)
+		if assert.True(t, ok) {
+			// detected from the content, not taken from the frame
+			assert.Equal(t, cover.MimeTypePNG, pf.MimeType)
+			assert.Equal(t, picture, pf.Picture)
+		}
+	}
+}
+
+func TestEmbeddedCoverInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []struct {
+		name    string
+		picture []byte
+	}{
+		{name: "garbage", picture: []byte("no image")},
+		{name: "broken image", picture: append(append([]byte{}, jpegFileContent...), "broken"...)},
+	} {
+		f := f // pin
+		t.Run(f.name, func(t *testing.T) {
+			t.Parallel()
+			root, fs := newTestFilesystem()
+			files := makeMP3Files(fs, root, header44100, 5, validFileName1, validFileName2)
+			withEmbeddedCover(fs, files[0], cover.MimeTypeJPEG, f.picture)
+			picture := validPNG()
+			withEmbeddedCover(fs, files[1], cover.MimeTypePNG, picture)
+			status := &bytes.Buffer{}
+
+			a := New(context.Background(), "", "test", "", status, fs, root, mp3binder.New(&testTagResolver{}), &testTagResolver{}, supportedLanguage, testUserConfigFile).(*application)
+
+			err := a.args(a.command, []string{"."})
+			if assert.NoError(t, err) && assert.NoError(t, a.run(a.command, nil)) {
+				assert.Contains(t, status.String(), "! Warning: skipping the cover embedded in '"+files[0]+"'")
+
+				// the cover of the next input file is used
+				pf, ok := frontCoverOfFile(fs, a.outputPath)
+				if assert.True(t, ok) {
+					assert.Equal(t, picture, pf.Picture)
+				}
+			}
+		})
+	}
+}
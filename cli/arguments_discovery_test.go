@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/carolynvs/aferox"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscoverInInputDirectory(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	dir := filepath.Join(root, "album")
+	_ = withTwoValidFiles(fs, dir)
+	coverFiles := makeImageFiles(fs, dir, validCoverFile1)
+	interlaceFiles := makeEmptyFiles(fs, dir, validInterlaceFile1)
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+
+	err := a.args(nil, []string{"album"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, coverFiles[0], a.coverFile)
+		assert.Equal(t, interlaceFiles[0], a.interlaceFile)
+	}
+}
+
+func TestDiscoveryOrder(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	first := filepath.Join(root, "first")
+	second := filepath.Join(root, "second")
+	_ = makeEmptyFiles(fs, first, validFileName1)
+	_ = makeEmptyFiles(fs, second, validFileName2)
+	workdirCover := makeImageFiles(fs, root, validCoverFile1)
+	firstCover := makeImageFiles(fs, first, validCoverFile1)
+	secondCover := makeImageFiles(fs, second, validCoverFile1)
+
+	for _, f := range []struct {
+		title    string
+		order    string
+		expected string
+	}{
+		{title: "Default", order: "", expected: firstCover[0]},
+		{title: "Inputs", order: "inputs", expected: firstCover[0]},
+		{title: "Inputs reversed", order: "inputs-reversed,workdir", expected: secondCover[0]},
+		{title: "Workdir first", order: "Workdir, inputs", expected: workdirCover[0]},
+		{title: "Output", order: "output", expected: workdirCover[0]},
+	} {
+		f := f // pin
+		t.Run(f.title, func(t *testing.T) {
+			t.Parallel()
+
+			a := newDefaultApplication(aferox.NewAferox(root, fs))
+			a.discoveryOrder = f.order
+			a.outputPath = validOutputFile
+
+			err := a.args(nil, []string{"first", "second"})
+			if assert.NoError(t, err) {
+				assert.Equal(t, f.expected, a.coverFile)
+			}
+		})
+	}
+}
+
+func TestInvalidDiscoveryOrder(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = withTwoValidFiles(fs, root)
+
+	for _, order := range []string{"home", "inputs,,workdir", "inputs,Inputs"} {
+		a := newDefaultApplication(aferox.NewAferox(root, fs))
+		a.discoveryOrder = order
+
+		err := a.args(nil, []string{"."})
+		assert.ErrorIs(t, err, ErrInvalidDiscovery, order)
+	}
+}
+
+func TestDiscoveryReportsDirectory(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	dir := filepath.Join(root, "album")
+	_ = withTwoValidFiles(fs, dir)
+	_ = makeImageFiles(fs, dir, validCoverFile1)
+
+	status := &bytes.Buffer{}
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+	a.status = status
+	a.verbose = true
+
+	err := a.args(nil, []string{"album"})
+	if assert.NoError(t, err) {
+		assert.Contains(t, status.String(), "The cover file '"+validCoverFile1+"' was discovered in: '"+dir+"'")
+	}
+}
@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carolynvs/aferox"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGap(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = withTwoValidFiles(fs, root)
+
+	a := newConfiguredApplication(fs, root, "--"+flagGap, "2s")
+
+	err := a.args(a.command, []string{"."})
+	if assert.NoError(t, err) {
+		assert.Equal(t, 2*time.Second, a.gap)
+	}
+}
+
+func TestNegativeGap(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = withTwoValidFiles(fs, root)
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+	a.gap = -time.Second
+
+	err := a.args(nil, []string{"."})
+	assert.ErrorIs(t, err, ErrInvalidGap)
+}
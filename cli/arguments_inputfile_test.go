@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/carolynvs/aferox"
 	"github.com/spf13/afero"
@@ -117,3 +118,112 @@ func TestInputFileWithOneFileAndAndArgument(t *testing.T) {
 		assert.Equal(t, mediaFilesOrdered, a.mediaFiles)
 	}
 }
+
+func TestInputFileRelativeToListWithComments(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	dir := filepath.Join(root, "lists")
+	mediaFiles := makeEmptyFiles(fs, dir, validFileName1, validFileName2)
+	afero.WriteFile(fs, filepath.Join(dir, inputFile), []byte("# the order\n\n"+validFileName2+"\n# "+validFileName1+"\n"+validFileName1+"\n"), 0o644)
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+	a.inputFile = filepath.Join("lists", inputFile)
+
+	err := a.args(nil, nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{mediaFiles[1], mediaFiles[0]}, a.mediaFiles)
+		assert.Empty(t, a.entries)
+	}
+}
+
+func TestInputFileGlobs(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	cd1 := makeEmptyFiles(fs, filepath.Join(root, "cd1"), "02.mp3", "01.mp3")
+	cd2 := makeEmptyFiles(fs, filepath.Join(root, "cd2"), "01.mp3")
+	afero.WriteFile(fs, filepath.Join(root, inputFile), []byte("cd1/*.mp3\ncd?/01.mp3\n"), 0o644)
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+	a.inputFile = inputFile
+
+	err := a.args(nil, nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{cd1[1], cd1[0], cd1[1], cd2[0]}, a.mediaFiles)
+	}
+}
+
+func TestInputFileBrackets(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	dir := filepath.Join(root, "Book [x]")
+	files := makeEmptyFiles(fs, dir, "01 [intro].mp3", "02.mp3", "03.mp3")
+	afero.WriteFile(fs, filepath.Join(dir, inputFile), []byte("01 [intro].mp3\n0[23].mp3\n01 [intro].mp3@0:01-\n"), 0o644)
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+	a.inputFile = filepath.Join("Book [x]", inputFile)
+
+	err := a.args(nil, nil)
+	if assert.NoError(t, err) {
+		// the existing file is not a pattern, the folder is not part of the pattern
+		assert.Equal(t, []string{files[0], files[1], files[2], files[0]}, a.mediaFiles)
+		assert.Equal(t, time.Second, a.entries[3].cut.start)
+	}
+}
+
+func TestInputFileGlobWithoutMatches(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = withTwoValidFiles(fs, root)
+	afero.WriteFile(fs, filepath.Join(root, inputFile), []byte("*.mp3\ncd1/*.mp3\n"), 0o644)
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+	a.inputFile = inputFile
+
+	err := a.args(nil, nil)
+	assert.ErrorIs(t, err, ErrFileNotFound)
+}
+
+func TestInputFilePlaylists(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	dir := filepath.Join(root, "book")
+	mediaFiles := makeEmptyFiles(fs, dir, validFileName1, validFileName2)
+
+	for _, f := range []struct {
+		name    string
+		content string
+	}{
+		{name: "list.m3u8", content: "#EXTM3U\n#EXTINF:12,The intro\n" + validFileName1 + "\n" + validFileName2 + "\n"},
+		{name: "list.m3u", content: "#EXTINF:12,The intro\nfile://" + mediaFiles[0] + "\n" + validFileName2 + "\n"},
+		{name: "list.pls", content: "[playlist]\nFile1=" + validFileName1 + "\nTitle1=The intro\nFile2=" + validFileName2 + "\nNumberOfEntries=2\n"},
+	} {
+		f := f // pin
+		t.Run(f.name, func(t *testing.T) {
+			t.Parallel()
+			afero.WriteFile(fs, filepath.Join(dir, f.name), []byte(f.content), 0o644)
+
+			a := newDefaultApplication(aferox.NewAferox(root, fs))
+			a.inputFile = filepath.Join(dir, f.name)
+
+			err := a.args(nil, nil)
+			if assert.NoError(t, err) && assert.Len(t, a.entries, 2) {
+				assert.Equal(t, mediaFiles, a.mediaFiles)
+				assert.Equal(t, "The intro", a.entries[0].chapterTitle)
+				assert.Equal(t, "", a.entries[1].chapterTitle)
+			}
+		})
+	}
+}
+
+func TestInputFileInvalidPlaylist(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = withTwoValidFiles(fs, root)
+	afero.WriteFile(fs, filepath.Join(root, "list.pls"), []byte("[playlist]\nFile1\n"), 0o644)
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+	a.inputFile = "list.pls"
+
+	err := a.args(nil, nil)
+	assert.ErrorIs(t, err, ErrInvalidFile)
+}
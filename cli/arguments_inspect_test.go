@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/carolynvs/aferox"
+	"github.com/crra/mp3binder/mp3binder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInspect(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = makeMP3Files(fs, root, header44100, 20, validFileName1)
+	_ = makeMP3Files(fs, root, header48000, 10, validFileName2)
+
+	status := &bytes.Buffer{}
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+	a.status = status
+
+	err := a.inspect(nil, []string{validFileName1, validFileName2})
+	if assert.NoError(t, err) {
+		assert.Contains(t, status.String(), "File:     "+validFileName1+"\nFormat:   MPEG-1 Layer III, 44100 Hz, stereo\nBitrate:  128 kbps (constant)\nDuration: 0:00.522 (20 frames)\n")
+		assert.Contains(t, status.String(), "\n\nFile:     "+validFileName2+"\nFormat:   MPEG-1 Layer III, 48000 Hz, stereo\n")
+	}
+}
+
+func TestInspectJSON(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = makeMP3Files(fs, root, header44100, 20, validFileName1)
+
+	status := &bytes.Buffer{}
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+	a.status = status
+	a.inspectJSON = true
+
+	err := a.inspect(nil, []string{validFileName1})
+	if assert.NoError(t, err) {
+		var inspections []inspection
+		if assert.NoError(t, json.Unmarshal(status.Bytes(), &inspections)) && assert.Len(t, inspections, 1) {
+			assert.Equal(t, validFileName1, inspections[0].File)
+			assert.Equal(t, 44100, inspections[0].SamplingRate)
+			assert.Equal(t, 128000, inspections[0].BitRate)
+			assert.False(t, inspections[0].VariableBitRate)
+			assert.Equal(t, 20, inspections[0].Frames)
+			assert.Nil(t, inspections[0].Header)
+			assert.Empty(t, inspections[0].Chapters)
+		}
+	}
+}
+
+func TestInspectInvalidFiles(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = makeEmptyFiles(fs, root, validFileName1)
+
+	for _, f := range []struct {
+		title string
+		args  []string
+		err   error
+	}{
+		{title: "no file", err: ErrNoInput},
+		{title: "no audio", args: []string{validFileName1}, err: mp3binder.ErrNoAudio},
+	} {
+		f := f // pin
+		t.Run(f.title, func(t *testing.T) {
+			t.Parallel()
+
+			a := newDefaultApplication(aferox.NewAferox(root, fs))
+			a.status = &bytes.Buffer{}
+
+			err := a.inspectArgs(nil, f.args)
+			if err == nil {
+				err = a.inspect(nil, f.args)
+			}
+
+			assert.ErrorIs(t, err, f.err)
+		})
+	}
+}
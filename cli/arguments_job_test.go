@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testJob = `
+output: book.mp3
+cover: cover.png
+interlace: silence.mp3
+tags:
+  TALB: The book
+  TPE1: [One, Two]
+inputs:
+  - file: validSampleFile1.mp3
+    chapter: Intro
+  - file: validSampleFile2.mp3
+    interlace: long.mp3
+  - validSampleFile3.mp3
+`
+
+func TestRunJob(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	dir := filepath.Join(root, "book")
+	files := withThreeValidFiles(fs, dir)
+	interlaceFiles := makeEmptyFiles(fs, dir, "silence.mp3", "long.mp3")
+	coverFiles := makeImageFiles(fs, dir, "cover.png")
+	writeConfig(fs, filepath.Join(dir, jobFileName), testJob)
+
+	a := newConfiguredApplication(fs, root)
+
+	err := a.runArgs(a.command, []string{"book"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, files, a.mediaFiles)
+		assert.Equal(t, filepath.Join(dir, "book.mp3"), a.outputPath)
+		assert.Equal(t, coverFiles[0], a.coverFile)
+		assert.Equal(t, interlaceFiles[0], a.interlaceFile)
+		assert.Equal(t, "The book", a.tags["TALB"])
+		assert.Equal(t, "One"+multipleValuesSeparator+"Two", a.tags["TPE1"])
+
+		if assert.Len(t, a.entries, 3) {
+			assert.Equal(t, "Intro", a.entries[0].chapterTitle)
+			assert.Nil(t, a.entries[0].interlace)
+			assert.Equal(t, interlaceFiles[1], *a.entries[1].interlace)
+		}
+	}
+}
+
+func TestRunJobFlagsOverJob(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	dir := filepath.Join(root, "book")
+	_ = withThreeValidFiles(fs, dir)
+	_ = makeEmptyFiles(fs, dir, "silence.mp3", "long.mp3")
+	_ = makeImageFiles(fs, dir, "cover.png")
+	writeConfig(fs, filepath.Join(dir, jobFileName), testJob)
+
+	a := newConfiguredApplication(fs, root, "--output", "other.mp3", "--tapply", "TALB=Other")
+
+	err := a.runArgs(a.command, []string{"book"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, filepath.Join(root, "other.mp3"), a.outputPath)
+		assert.Equal(t, "Other", a.tags["TALB"])
+	}
+}
+
+func TestRunJobPrecedence(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	dir := filepath.Join(root, "book")
+	_ = withThreeValidFiles(fs, dir)
+	interlaceFiles := makeEmptyFiles(fs, dir, "silence.mp3", "long.mp3", "intro.mp3")
+	_ = makeEmptyFiles(fs, root, "user-silence.mp3", "user-intro.mp3")
+	coverFiles := makeImageFiles(fs, root, "user.png", "flag.png")
+	writeConfig(fs, filepath.Join(dir, jobFileName), testJob)
+	writeConfig(fs, testUserConfigFile, "interlace: user-silence.mp3\ncover: user.png\nintro: user-intro.mp3\noutput: user.mp3\ntapply: TALB=User,TCOM=User\n")
+	writeConfig(fs, filepath.Join(dir, folderConfigFileName), "intro: book/intro.mp3\n")
+
+	a := newConfiguredApplication(fs, root, "--cover", "flag.png")
+
+	err := a.runArgs(a.command, []string{"book"})
+	if assert.NoError(t, err) {
+		// the job over the configs
+		assert.Equal(t, interlaceFiles[0], a.interlaceFile)
+		assert.Equal(t, filepath.Join(dir, "book.mp3"), a.outputPath)
+		assert.Equal(t, "The book", a.tags["TALB"])
+		// the command line over the job
+		assert.Equal(t, coverFiles[1], a.coverFile)
+		// the configs for the options the job doesn't set
+		assert.Equal(t, interlaceFiles[2], a.introFile)
+		assert.Equal(t, "User", a.tags["TCOM"])
+	}
+}
+
+func TestInvalidJob(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []struct {
+		title string
+		job   string
+		flags []string
+		err   error
+	}{
+		{title: "No inputs", job: "output: book.mp3\n", err: ErrInvalidJob},
+		{title: "Input without file", job: "inputs:\n  - chapter: Intro\n", err: ErrInvalidJob},
+		{title: "Invalid YAML", job: "inputs: [\n", err: ErrInvalidJob},
+		{title: "With input file", job: "inputs: [validSampleFile1.mp3, validSampleFile2.mp3]\n", flags: []string{"--input", "list.txt"}, err: ErrInvalidJob},
+		{title: "Output file as input", job: "output: validSampleFile2.mp3\ninputs: [validSampleFile1.mp3, validSampleFile2.mp3, validSampleFile3.mp3]\n", flags: []string{"--force"}, err: ErrInvalidJob},
+		{title: "Missing input file", job: "inputs: [validSampleFile1.mp3, missing.mp3]\n", err: ErrFileNotFound},
+		{title: "Missing interlace file", job: "inputs:\n  - file: validSampleFile1.mp3\n    interlace: missing.mp3\n  - validSampleFile2.mp3\n", err: ErrFileNotFound},
+	} {
+		f := f // pin
+		t.Run(f.title, func(t *testing.T) {
+			t.Parallel()
+			root, fs := newTestFilesystem()
+			_ = withThreeValidFiles(fs, root)
+			writeConfig(fs, filepath.Join(root, jobFileName), f.job)
+
+			a := newConfiguredApplication(fs, root, f.flags...)
+
+			err := a.runArgs(a.command, nil)
+			assert.ErrorIs(t, err, f.err)
+		})
+	}
+}
+
+func TestJobWithFolderInput(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = withThreeValidFiles(fs, root)
+	_ = makeMP3Files(fs, filepath.Join(root, "disc2"), header44100, 5, validFileName1, validFileName2)
+	writeConfig(fs, filepath.Join(root, jobFileName), "inputs: [validSampleFile1.mp3, disc2]\n")
+
+	a := newConfiguredApplication(fs, root)
+
+	err := a.runArgs(a.command, nil)
+	if assert.ErrorIs(t, err, ErrInvalidJob) {
+		assert.Contains(t, err.Error(), "input 2 'disc2' is a folder")
+	}
+}
+
+func TestRunJobMissing(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = withTwoValidFiles(fs, root)
+
+	a := newConfiguredApplication(fs, root)
+
+	err := a.runArgs(a.command, []string{"."})
+	assert.ErrorIs(t, err, ErrFileNotFound)
+}
+
+func TestInsertSpacersOfEntries(t *testing.T) {
+	t.Parallel()
+	long := "long.mp3"
+	none := ""
+
+	a := &application{
+		mediaFiles:        []string{"1.mp3", "2.mp3", "3.mp3", "4.mp3"},
+		entries:           []entry{{chapterTitle: "Intro"}, {interlace: &long}, {interlace: &none}, {}},
+		interlaceFile:     "silence.mp3",
+		copyTagsFromIndex: 3,
+	}
+
+	a.insertSpacers()
+
+	assert.Equal(t, []string{"1.mp3", "silence.mp3", "2.mp3", "long.mp3", "3.mp3", "4.mp3"}, a.mediaFiles)
+	assert.Equal(t, 5, a.copyTagsFromIndex)
+	assert.Equal(t, "Intro", a.entries[0].chapterTitle)
+	for i, spacer := range []bool{false, true, false, true, false, false} {
+		assert.Equal(t, spacer, a.isSpacer(i), i)
+	}
+}
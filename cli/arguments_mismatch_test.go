@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/carolynvs/aferox"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	// MPEG-1 Layer III, 128 kbps, stereo
+	header44100 = []byte{0xFF, 0xFB, 0x90, 0x00}
+	header48000 = []byte{0xFF, 0xFB, 0x94, 0x00}
+)
+
+// makeMP3Files creates files of silent frames with the header.
+func makeMP3Files(fs afero.Fs, dir string, header []byte, frames int, names ...string) []string {
+	frameLength := 144 * 128000 / 44100
+	if header[2] == header48000[2] {
+		frameLength = 144 * 128000 / 48000
+	}
+
+	frame := make([]byte, frameLength)
+	copy(frame, header)
+
+	files := make([]string, len(names))
+	for i, name := range names {
+		files[i] = filepath.Join(dir, name)
+		if err := afero.WriteFile(fs, files[i], bytes.Repeat(frame, frames), 0o644); err != nil {
+			panic(err)
+		}
+	}
+
+	return files
+}
+
+func TestInterlaceFileMatches(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = makeMP3Files(fs, root, header44100, 20, validFileName1, validFileName2)
+	interlaceFiles := makeMP3Files(fs, root, header44100, 10, validInterlaceFile1)
+
+	status := &bytes.Buffer{}
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+	a.status = status
+	a.interlaceFile = interlaceFiles[0]
+
+	err := a.args(nil, []string{"."})
+	if assert.NoError(t, err) {
+		assert.Equal(t, interlaceFiles[0], a.interlaceFile)
+		assert.Empty(t, status.String())
+	}
+}
+
+func TestInterlaceFileMismatch(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = makeMP3Files(fs, root, header44100, 20, validFileName1, validFileName2)
+	interlaceFiles := makeMP3Files(fs, root, header48000, 10, validInterlaceFile1)
+
+	silence := 10 * 1152 * time.Second / 48000
+
+	for _, f := range []struct {
+		mismatch  string
+		interlace string
+		gap       time.Duration
+		warning   bool
+		err       error
+	}{
+		{mismatch: "", interlace: interlaceFiles[0], warning: true},
+		{mismatch: mismatchWarn, interlace: interlaceFiles[0], warning: true},
+		{mismatch: mismatchSilence, gap: silence},
+		{mismatch: "Silence", gap: silence},
+		{mismatch: mismatchError, err: ErrInterlaceMismatch},
+		{mismatch: "replace", err: ErrInvalidMismatch},
+	} {
+		f := f // pin
+		t.Run(f.mismatch, func(t *testing.T) {
+			t.Parallel()
+
+			status := &bytes.Buffer{}
+			a := newDefaultApplication(aferox.NewAferox(root, fs))
+			a.status = status
+			a.interlaceFile = interlaceFiles[0]
+			a.interlaceMismatch = f.mismatch
+
+			err := a.args(nil, []string{"."})
+			if f.err != nil {
+				assert.ErrorIs(t, err, f.err)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, f.interlace, a.interlaceFile)
+				assert.Equal(t, f.warning, status.Len() > 0)
+				if f.gap > 0 {
+					assert.Equal(t, f.gap, a.gapAfter(0))
+				}
+			}
+		})
+	}
+}
+
+func TestJobInterlaceFileMismatch(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = makeMP3Files(fs, root, header44100, 20, validFileName1, validFileName2)
+	_ = makeMP3Files(fs, root, header48000, 10, validInterlaceFile1)
+	writeConfig(fs, filepath.Join(root, jobFileName), "inputs:\n  - file: "+validFileName1+"\n    interlace: "+validInterlaceFile1+"\n  - "+validFileName2+"\n")
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+	a.interlaceMismatch = mismatchSilence
+
+	err := a.runArgs(nil, []string{"."})
+	if assert.NoError(t, err) && assert.Len(t, a.entries, 2) {
+		assert.Equal(t, "", *a.entries[0].interlace)
+		assert.Equal(t, 10*1152*time.Second/48000, a.entries[0].gap)
+	}
+}
+
+func TestInterlaceFileMismatchKeepsGap(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = makeMP3Files(fs, root, header44100, 20, validFileName1, validFileName2)
+	_ = makeMP3Files(fs, root, header44100, 20, filepath.Join("disc2", validFileName1))
+	interlaceFiles := makeMP3Files(fs, root, header48000, 10, validInterlaceFile1)
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+	a.interlaceFile = interlaceFiles[0]
+	a.interlaceMismatch = mismatchSilence
+	a.gap = time.Second
+
+	err := a.args(nil, []string{".", "disc2"})
+	if assert.NoError(t, err) && assert.Len(t, a.entries, 3) {
+		// the gap of the user is kept, the silence takes the place of the interlace file
+		assert.Equal(t, time.Second, a.gap)
+		assert.Equal(t, 10*1152*time.Second/48000, a.gapAfter(0))
+		assert.Equal(t, 10*1152*time.Second/48000, a.gapAfter(1))
+	}
+}
+
+func TestSpacerFileMismatch(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = makeMP3Files(fs, root, header44100, 20, validFileName1, validFileName2)
+	_ = makeMP3Files(fs, root, header44100, 20, filepath.Join("disc2", validFileName1))
+	spacerFiles := makeMP3Files(fs, filepath.Join(root, "spacers"), header48000, 10, "intro.mp3", "outro.mp3", "pause.mp3")
+
+	silence := 10 * 1152 * time.Second / 48000
+
+	for _, f := range []struct {
+		name     string
+		file     string
+		spacer   func(a *application) *string
+		replaced bool
+	}{
+		{name: "intro", file: spacerFiles[0], spacer: func(a *application) *string { return &a.introFile }},
+		{name: "outro", file: spacerFiles[1], spacer: func(a *application) *string { return &a.outroFile }},
+		{name: "folder spacer", file: spacerFiles[2], spacer: func(a *application) *string { return &a.folderSpacer }, replaced: true},
+	} {
+		f := f // pin
+		t.Run(f.name, func(t *testing.T) {
+			t.Parallel()
+
+			for _, mismatch := range []string{mismatchWarn, mismatchSilence, mismatchError} {
+				status := &bytes.Buffer{}
+				a := newDefaultApplication(aferox.NewAferox(root, fs))
+				a.status = status
+				a.interlaceMismatch = mismatch
+				*f.spacer(a) = f.file
+
+				err := a.args(nil, []string{".", "disc2"})
+				switch mismatch {
+				case mismatchError:
+					assert.ErrorIs(t, err, ErrInterlaceMismatch)
+				case mismatchWarn:
+					if assert.NoError(t, err) {
+						assert.Contains(t, status.String(), "! Warning: the "+f.name+" '"+f.file+"'")
+					}
+				case mismatchSilence:
+					if !assert.NoError(t, err) {
+						continue
+					}
+
+					if f.replaced {
+						assert.Empty(t, *f.spacer(a))
+						assert.Equal(t, time.Duration(0), a.gapAfter(0), "files of the same folder")
+						assert.Equal(t, silence, a.gapAfter(1))
+					} else {
+						// there is no silence before the first or after the last input file
+						assert.Equal(t, f.file, *f.spacer(a))
+						assert.Contains(t, status.String(), "! Warning: the "+f.name+" '"+f.file+"' does not match the input files and is kept")
+					}
+				}
+			}
+		})
+	}
+}
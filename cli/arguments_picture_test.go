@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/carolynvs/aferox"
+	"github.com/crra/id3v2/v2"
+	"github.com/crra/mp3binder/image/cover"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPictures(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = withTwoValidFiles(fs, root)
+	files := makeImageFiles(fs, root, "b.png", "a.png")
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+	a.noDiscovery = true
+	a.pictureArgs = []string{"back=b.png", "Artist = a.png"}
+
+	err := a.args(nil, []string{"."})
+	if assert.NoError(t, err) && assert.Len(t, a.pictures, 2) {
+		assert.Equal(t, byte(id3v2.PTBackCover), a.pictures[0].kind.pictureType)
+		assert.Equal(t, files[0], a.pictures[0].file)
+		assert.Equal(t, cover.MimeTypePNG, a.pictures[0].mimeType)
+		assert.Equal(t, byte(id3v2.PTArtistPerformer), a.pictures[1].kind.pictureType)
+		assert.Equal(t, files[1], a.pictures[1].file)
+	}
+}
+
+func TestFrontCoverAsPicture(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = withTwoValidFiles(fs, root)
+	files := makeImageFiles(fs, root, "front.png")
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+	a.pictureArgs = []string{"front=front.png"}
+
+	err := a.args(nil, []string{"."})
+	if assert.NoError(t, err) {
+		assert.Equal(t, files[0], a.coverFile)
+		assert.Empty(t, a.pictures)
+	}
+}
+
+func TestInvalidPictures(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = withTwoValidFiles(fs, root)
+	_ = makeImageFiles(fs, root, validCoverFile3, "back.png")
+	_ = makeEmptyFiles(fs, root, "empty.png")
+
+	for _, f := range []struct {
+		title    string
+		pictures []string
+		cover    string
+		err      error
+	}{
+		{title: "Without type", pictures: []string{"back.png"}, err: ErrInvalidPicture},
+		{title: "Without file", pictures: []string{"back="}, err: ErrInvalidPicture},
+		{title: "Unknown type", pictures: []string{"side=back.png"}, err: ErrInvalidPicture},
+		{title: "Type set twice", pictures: []string{"back=back.png", "BACK=back.png"}, err: ErrInvalidPicture},
+		{title: "Front cover set twice", pictures: []string{"front=back.png"}, cover: validCoverFile3, err: ErrInvalidPicture},
+		{title: "Non-existing file", pictures: []string{"back=missing.png"}, err: ErrFileNotFound},
+		{title: "No image", pictures: []string{"back=empty.png"}, err: ErrInvalidFile},
+	} {
+		f := f // pin
+		t.Run(f.title, func(t *testing.T) {
+			t.Parallel()
+
+			a := newDefaultApplication(aferox.NewAferox(root, fs))
+			a.pictureArgs = f.pictures
+			a.coverFile = f.cover
+
+			err := a.args(nil, []string{"."})
+			assert.ErrorIs(t, err, f.err)
+		})
+	}
+}
+
+func TestDiscoverPictures(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = withTwoValidFiles(fs, root)
+	files := makeImageFiles(fs, root, "back.jpg", "ARTIST.png", "disc.jpg", validCoverFile1)
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+
+	err := a.args(nil, []string{"."})
+	if assert.NoError(t, err) && assert.Len(t, a.pictures, 3) {
+		assert.Equal(t, files[3], a.coverFile)
+		assert.Equal(t, files[0], a.pictures[0].file)
+		assert.Equal(t, files[1], a.pictures[1].file)
+		assert.Equal(t, files[2], a.pictures[2].file)
+		assert.Equal(t, byte(id3v2.PTMedia), a.pictures[2].kind.pictureType)
+	}
+}
+
+func TestNoPictureDiscovery(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = withTwoValidFiles(fs, root)
+	_ = makeImageFiles(fs, root, "back.jpg", "artist.jpg")
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+	a.noDiscovery = true
+
+	err := a.args(nil, []string{"."})
+	if assert.NoError(t, err) {
+		assert.Empty(t, a.pictures)
+	}
+}
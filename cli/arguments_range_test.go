@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/carolynvs/aferox"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTimeRange(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []struct {
+		input    string
+		expected timeRange
+		err      error
+	}{
+		{input: "0:05-", expected: timeRange{start: 5 * time.Second}},
+		{input: "-45:00", expected: timeRange{end: 45 * time.Minute}},
+		{input: "1:00-1:02:03.5", expected: timeRange{start: time.Minute, end: time.Hour + 2*time.Minute + 3500*time.Millisecond}},
+		{input: "90-120", expected: timeRange{start: 90 * time.Second, end: 120 * time.Second}},
+		{input: "-", expected: timeRange{}},
+		{input: "1:00", err: ErrInvalidRange},
+		{input: "1-2-3", err: ErrInvalidRange},
+		{input: "2:00-1:00", err: ErrInvalidRange},
+		{input: "1:60-", err: ErrInvalidRange},
+		{input: "1:2:3:4-", err: ErrInvalidRange},
+		{input: "a-", err: ErrInvalidRange},
+		{input: "NaN-", err: ErrInvalidRange},
+	} {
+		f := f // pin
+		t.Run(f.input, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := parseTimeRange(f.input)
+			if f.err != nil {
+				assert.ErrorIs(t, err, f.err)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, f.expected, r)
+			}
+		})
+	}
+}
+
+func TestArgumentsWithTimeRanges(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	mediaFiles := withTwoValidFiles(fs, root)
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+
+	err := a.args(nil, []string{validFileName1 + "@0:05-", validFileName2, validFileName1 + "@-1:00"})
+	if assert.NoError(t, err) && assert.Len(t, a.entries, 3) {
+		assert.Equal(t, []string{mediaFiles[0], mediaFiles[1], mediaFiles[0]}, a.mediaFiles)
+		assert.Equal(t, timeRange{start: 5 * time.Second}, a.entries[0].cut)
+		assert.Equal(t, timeRange{}, a.entries[1].cut)
+		assert.Equal(t, timeRange{end: time.Minute}, a.entries[2].cut)
+	}
+}
+
+func TestFileNameWithTimeRangeSeparator(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	mediaFiles := makeEmptyFiles(fs, root, "live@home-1.mp3", validFileName2)
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+
+	err := a.args(nil, []string{"live@home-1.mp3", validFileName2})
+	if assert.NoError(t, err) {
+		assert.Equal(t, mediaFiles, a.mediaFiles)
+		assert.Empty(t, a.entries)
+	}
+}
+
+func TestFolderWithTimeRangeSeparator(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	mediaFiles := makeEmptyFiles(fs, filepath.Join(root, "a@b"), "x-y.mp3", "1-2.mp3")
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+
+	err := a.args(nil, []string{"a@b/x-y.mp3", "a@b/1-2.mp3"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, mediaFiles, a.mediaFiles)
+		assert.Empty(t, a.entries)
+	}
+}
+
+func TestInvalidTimeRanges(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = withTwoValidFiles(fs, root)
+
+	for _, f := range []struct {
+		title string
+		args  []string
+		err   error
+	}{
+		{title: "invalid range", args: []string{validFileName1 + "@5-1", validFileName2}, err: ErrInvalidRange},
+		{title: "directory", args: []string{".@0:05-"}, err: ErrInvalidRange},
+		{title: "missing file", args: []string{"missing.mp3@0:05-", validFileName2}, err: ErrFileNotFound},
+		{title: "missing file in a folder with a separator", args: []string{"/a@b/x-y.mp3", validFileName2}, err: ErrFileNotFound},
+		{title: "not a time range", args: []string{validFileName1 + "@intro-", validFileName2}, err: ErrFileNotFound},
+	} {
+		f := f // pin
+		t.Run(f.title, func(t *testing.T) {
+			t.Parallel()
+
+			a := newDefaultApplication(aferox.NewAferox(root, fs))
+
+			err := a.args(nil, f.args)
+			assert.ErrorIs(t, err, f.err)
+		})
+	}
+}
+
+func TestInputFileWithTimeRanges(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = withTwoValidFiles(fs, root)
+	afero.WriteFile(fs, filepath.Join(root, "list.m3u"), []byte("#EXTINF:1,The intro\n"+validFileName1+"@0:05-\n"+validFileName2+"\n"), 0o644)
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+	a.inputFile = "list.m3u"
+
+	err := a.args(nil, nil)
+	if assert.NoError(t, err) && assert.Len(t, a.entries, 2) {
+		assert.Equal(t, "The intro", a.entries[0].chapterTitle)
+		assert.Equal(t, timeRange{start: 5 * time.Second}, a.entries[0].cut)
+	}
+}
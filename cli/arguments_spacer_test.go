@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/carolynvs/aferox"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpacerFiles(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = withTwoValidFiles(fs, root)
+	spacers := makeEmptyFiles(fs, filepath.Join(root, "spacers"), "intro.mp3", "outro.mp3", "pause.mp3")
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+	a.introFile = "spacers/intro.mp3"
+	a.outroFile = spacers[1]
+	a.folderSpacer = "spacers/pause.mp3"
+
+	err := a.args(nil, []string{"."})
+	if assert.NoError(t, err) {
+		assert.Equal(t, spacers[0], a.introFile)
+		assert.Equal(t, spacers[1], a.outroFile)
+		assert.Equal(t, spacers[2], a.folderSpacer)
+	}
+}
+
+func TestInvalidSpacerFiles(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	_ = withTwoValidFiles(fs, root)
+	_ = makeEmptyFiles(fs, root, "intro.wav")
+
+	for _, f := range []struct {
+		title string
+		set   func(a *application)
+		err   error
+	}{
+		{title: "Missing intro", set: func(a *application) { a.introFile = "missing.mp3" }, err: ErrFileNotFound},
+		{title: "Invalid intro", set: func(a *application) { a.introFile = "intro.wav" }, err: ErrInvalidFile},
+		{title: "Missing outro", set: func(a *application) { a.outroFile = "missing.mp3" }, err: ErrFileNotFound},
+		{title: "Missing folder spacer", set: func(a *application) { a.folderSpacer = "missing.mp3" }, err: ErrFileNotFound},
+	} {
+		f := f // pin
+		t.Run(f.title, func(t *testing.T) {
+			t.Parallel()
+
+			a := newDefaultApplication(aferox.NewAferox(root, fs))
+			f.set(a)
+
+			err := a.args(nil, []string{"."})
+			assert.ErrorIs(t, err, f.err)
+		})
+	}
+}
+
+func TestInsertSpacers(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []struct {
+		title        string
+		interlace    string
+		folderSpacer string
+		intro        string
+		outro        string
+		expected     []string
+	}{
+		{
+			title:    "Intro and outro",
+			intro:    "intro.mp3",
+			outro:    "outro.mp3",
+			expected: []string{"intro.mp3", "cd1/1.mp3", "cd1/2.mp3", "cd2/1.mp3", "outro.mp3"},
+		},
+		{
+			title:     "Interlace",
+			interlace: "silence.mp3",
+			expected:  []string{"cd1/1.mp3", "silence.mp3", "cd1/2.mp3", "silence.mp3", "cd2/1.mp3"},
+		},
+		{
+			title:        "Folder spacer",
+			folderSpacer: "pause.mp3",
+			expected:     []string{"cd1/1.mp3", "cd1/2.mp3", "pause.mp3", "cd2/1.mp3"},
+		},
+		{
+			title:        "All",
+			interlace:    "silence.mp3",
+			folderSpacer: "pause.mp3",
+			intro:        "intro.mp3",
+			outro:        "outro.mp3",
+			expected:     []string{"intro.mp3", "cd1/1.mp3", "silence.mp3", "cd1/2.mp3", "pause.mp3", "cd2/1.mp3", "outro.mp3"},
+		},
+	} {
+		f := f // pin
+		t.Run(f.title, func(t *testing.T) {
+			t.Parallel()
+
+			a := &application{
+				mediaFiles:        []string{"cd1/1.mp3", "cd1/2.mp3", "cd2/1.mp3"},
+				entries:           make([]entry, 3),
+				interlaceFile:     f.interlace,
+				folderSpacer:      f.folderSpacer,
+				introFile:         f.intro,
+				outroFile:         f.outro,
+				copyTagsFromIndex: 3,
+			}
+
+			assert.True(t, a.hasSpacers())
+			a.insertSpacers()
+
+			assert.Equal(t, f.expected, a.mediaFiles)
+			assert.Equal(t, "cd2/1.mp3", a.mediaFiles[a.copyTagsFromIndex-1])
+			for i, file := range a.mediaFiles {
+				assert.Equal(t, filepath.Dir(file) == ".", a.isSpacer(i), file)
+			}
+		})
+	}
+}
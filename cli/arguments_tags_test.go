@@ -1,9 +1,16 @@
 package cli
 
 import (
+	"bytes"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/carolynvs/aferox"
+	"github.com/crra/mp3binder/encoding/tagfile"
+	"github.com/crra/mp3binder/mp3binder"
+	"github.com/crra/mp3binder/mp3binder/tags"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -89,3 +96,310 @@ func TestApplyTags(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeTags(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	withTwoValidFiles(fs, root)
+
+	for _, f := range []struct {
+		title    string
+		args     string
+		expected map[string]mp3binder.MergeStrategy
+		err      error
+	}{
+		{
+			title:    "Single tag",
+			args:     "TALB=common",
+			expected: map[string]mp3binder.MergeStrategy{"TALB": mp3binder.MergeMostCommon},
+		},
+		{
+			title: "Multiple tags",
+			args:  "TALB=common,TCOM=concat,*=first",
+			expected: map[string]mp3binder.MergeStrategy{
+				"TALB": mp3binder.MergeMostCommon,
+				"TCOM": mp3binder.MergeConcat,
+				"*":    mp3binder.MergeFirst,
+			},
+		},
+		{
+			title:    "Case insensitive strategy",
+			args:     "TPE1=Common",
+			expected: map[string]mp3binder.MergeStrategy{"TPE1": mp3binder.MergeMostCommon},
+		},
+		{
+			title: "Unknown strategy",
+			args:  "TALB=longest",
+			err:   ErrInvalidStrategy,
+		},
+		{
+			title: "Missing strategy",
+			args:  "TALB",
+			err:   ErrInvalidStrategy,
+		},
+	} {
+		f := f // pin
+		t.Run(f.title, func(t *testing.T) {
+			t.Parallel()
+
+			a := newDefaultApplication(aferox.NewAferox(root, fs))
+			a.mergeTags = f.args
+
+			err := a.args(nil, []string{"."})
+			if f.err != nil {
+				assert.ErrorIs(t, err, f.err)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, f.expected, a.mergeStrategies)
+			}
+		})
+	}
+}
+
+func TestCopyAndMergeTags(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	withTwoValidFiles(fs, root)
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+	a.copyTagsFromIndex = 1
+	a.mergeTags = "TALB=common"
+
+	err := a.args(nil, []string{"."})
+	assert.ErrorIs(t, err, ErrInvalidStrategy)
+}
+
+func TestTagsFile(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []struct {
+		title    string
+		name     string
+		content  string
+		primed   map[string]string
+		expected map[string]string
+	}{
+		{
+			title:    "Key value",
+			name:     "tags.txt",
+			content:  "# album\nTALB=Album\nTXXX:ASIN=B000\n",
+			primed:   map[string]string{},
+			expected: map[string]string{"TALB": "Album", "TXXX:ASIN": "B000"},
+		},
+		{
+			title:    "JSON with multiple values",
+			name:     "tags.json",
+			content:  `{"TPE1": ["One", "Two"], "COMM:eng:Notes": ["First", "Second"]}`,
+			primed:   map[string]string{},
+			expected: map[string]string{"TPE1": "One\x00Two", "COMM:eng:Notes": "First\nSecond"},
+		},
+		{
+			title:    "YAML removing primed",
+			name:     "tags.yaml",
+			content:  "TALB: Album\nfoo: ''",
+			primed:   map[string]string{"foo": "bar"},
+			expected: map[string]string{"TALB": "Album", "foo": ""},
+		},
+	} {
+		f := f // pin
+		t.Run(f.title, func(t *testing.T) {
+			t.Parallel()
+			root, fs := newTestFilesystem()
+			withTwoValidFiles(fs, root)
+			afero.WriteFile(fs, filepath.Join(root, f.name), []byte(f.content), 0o644)
+
+			a := newDefaultApplication(aferox.NewAferox(root, fs))
+			a.tagsFile = f.name
+			a.tags = f.primed
+
+			err := a.args(nil, []string{"."})
+			if assert.NoError(t, err) {
+				assert.Equal(t, f.expected, a.tags)
+			}
+		})
+	}
+}
+
+func TestTagsFileBeforeApplyTags(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	withTwoValidFiles(fs, root)
+	afero.WriteFile(fs, filepath.Join(root, "tags.txt"), []byte("TALB=From file\nTPE1=Artist"), 0o644)
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+	a.tagsFile = "tags.txt"
+	a.applyTags = "TALB='From argument'"
+	a.tags = map[string]string{}
+
+	err := a.args(nil, []string{"."})
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]string{"TALB": "From argument", "TPE1": "Artist"}, a.tags)
+	}
+}
+
+func TestNonExistingTagsFile(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	withTwoValidFiles(fs, root)
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+	a.tagsFile = "tags.yaml"
+
+	err := a.args(nil, []string{"."})
+	assert.ErrorIs(t, err, ErrFileNotFound)
+}
+
+func TestInvalidTagsFile(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	withTwoValidFiles(fs, root)
+	afero.WriteFile(fs, filepath.Join(root, "tags.json"), []byte("{"), 0o644)
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+	a.tagsFile = "tags.json"
+
+	err := a.args(nil, []string{"."})
+	assert.ErrorIs(t, err, tagfile.ErrInvalidFormat)
+}
+
+func TestApplyStructuredTags(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	withTwoValidFiles(fs, root)
+	afero.WriteFile(fs, filepath.Join(root, "lyrics.txt"), []byte("First line\nSecond line\n"), 0o644)
+
+	for _, f := range []struct {
+		title    string
+		args     string
+		expected map[string]string
+	}{
+		{
+			title:    "Comment in brackets",
+			args:     "COMM[eng:Notes]='Some notes'",
+			expected: map[string]string{"COMM:eng:Notes": "Some notes"},
+		},
+		{
+			title:    "User defined text in brackets",
+			args:     "TXXX[ASIN]=B000",
+			expected: map[string]string{"TXXX:ASIN": "B000"},
+		},
+		{
+			title:    "Lyrics from file",
+			args:     "USLT[eng]=@lyrics.txt",
+			expected: map[string]string{"USLT:eng:": "First line\nSecond line"},
+		},
+		{
+			title:    "Link and popularimeter",
+			args:     "WXXX[Shop]='https://example.com', POPM[user@example.com]=196/12",
+			expected: map[string]string{"WXXX:Shop": "https://example.com", "POPM:user@example.com": "196/12"},
+		},
+	} {
+		f := f // pin
+		t.Run(f.title, func(t *testing.T) {
+			t.Parallel()
+
+			a := newDefaultApplication(aferox.NewAferox(root, fs))
+			a.applyTags = f.args
+			a.tags = map[string]string{}
+
+			err := a.args(nil, []string{"."})
+			if assert.NoError(t, err) {
+				assert.Equal(t, f.expected, a.tags)
+			}
+		})
+	}
+}
+
+func TestApplyTagsWithNonExistingFile(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	withTwoValidFiles(fs, root)
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+	a.applyTags = "USLT[eng]=@lyrics.txt"
+
+	err := a.args(nil, []string{"."})
+	assert.ErrorIs(t, err, ErrFileNotFound)
+}
+
+func TestApplyInvalidTags(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	withTwoValidFiles(fs, root)
+
+	for _, f := range []struct {
+		title string
+		args  string
+	}{
+		{title: "Language with two letters", args: "COMM[en:Notes]=text"},
+		{title: "User defined text without description", args: "TXXX=text"},
+		{title: "Qualifiers for text frame", args: "TALB[foo]=Album"},
+		{title: "Link without url", args: "WXXX[Shop]=example"},
+		{title: "Popularimeter without email", args: "POPM=196"},
+		{title: "Popularimeter with invalid rating", args: "POPM[user@example.com]=256"},
+	} {
+		f := f // pin
+		t.Run(f.title, func(t *testing.T) {
+			t.Parallel()
+
+			a := newDefaultApplication(aferox.NewAferox(root, fs))
+			a.tagResolver = tags.NewV24(ErrTagNonStandard, ErrTagInvalid, ErrTagInvalidValue)
+			a.applyTags = f.args
+
+			err := a.args(nil, []string{"."})
+			assert.ErrorIs(t, err, ErrTagInvalid)
+		})
+	}
+}
+
+func TestTagValueRules(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	withTwoValidFiles(fs, root)
+
+	for _, f := range []struct {
+		title    string
+		args     string
+		strict   bool
+		expected map[string]string
+		warning  bool
+		err      error
+	}{
+		{title: "Normalized", args: "TRCK=' 03 / 12', TCON=17, TLAN=ENG, TDRC='2021-05-01 10:30'", expected: map[string]string{"TRCK": "3/12", "TCON": "Rock", "TLAN": "eng", "TDRC": "2021-05-01T10:30"}},
+		{title: "Normalized strict", args: "TRCK=3/12, TCON=rock", strict: true, expected: map[string]string{"TRCK": "3/12", "TCON": "Rock"}},
+		{title: "Invalid value with warning", args: "TRCK=third", expected: map[string]string{"TRCK": "third"}, warning: true},
+		{title: "Invalid value strict", args: "TRCK=third", strict: true, err: ErrTagInvalidValue},
+		{title: "Invalid timestamp strict", args: "TDRC=01.05.2021", strict: true, err: ErrTagInvalidValue},
+		{title: "Invalid language strict", args: "TLAN=english", strict: true, err: ErrTagInvalidValue},
+		{title: "Free text genre strict", args: "TCON='Audiobook; Sci-Fi'", strict: true, expected: map[string]string{"TCON": "Audiobook; Sci-Fi"}},
+		{title: "Unknown genre reference strict", args: "TCON=192", strict: true, err: ErrTagInvalidValue},
+		{title: "Non-standard tag strict", args: "FOO=bar", strict: true, err: ErrTagNonStandard},
+		{title: "Invalid frame without strict", args: "TXXX=text", err: ErrTagInvalid},
+	} {
+		f := f // pin
+		t.Run(f.title, func(t *testing.T) {
+			t.Parallel()
+
+			status := &bytes.Buffer{}
+			a := newDefaultApplication(aferox.NewAferox(root, fs))
+			a.tagResolver = tags.NewV24(ErrTagNonStandard, ErrTagInvalid, ErrTagInvalidValue)
+			a.status = status
+			a.applyTags = f.args
+			a.strict = f.strict
+			a.tags = map[string]string{}
+
+			err := a.args(nil, []string{"."})
+			if f.err != nil {
+				assert.ErrorIs(t, err, f.err)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, f.expected, a.tags)
+				assert.Equal(t, f.warning, strings.Contains(status.String(), "Warning"), status.String())
+			}
+		})
+	}
+}
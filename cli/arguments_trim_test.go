@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/crra/mp3binder/mp3binder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrimThreshold(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []struct {
+		title string
+		flags []string
+		err   error
+	}{
+		{title: "default", flags: []string{"--" + flagTrimSilence}},
+		{title: "negative", flags: []string{"--" + flagTrimSilence, "--" + flagTrimThreshold, "-45.5"}},
+		{title: "zero", flags: []string{"--" + flagTrimSilence, "--" + flagTrimThreshold, "0"}, err: ErrInvalidThreshold},
+		{title: "positive without trimming", flags: []string{"--" + flagTrimThreshold, "10"}},
+	} {
+		f := f // pin
+		t.Run(f.title, func(t *testing.T) {
+			t.Parallel()
+			root, fs := newTestFilesystem()
+			_ = withTwoValidFiles(fs, root)
+
+			a := newConfiguredApplication(fs, root, f.flags...)
+
+			err := a.args(a.command, []string{"."})
+			if f.err != nil {
+				assert.ErrorIs(t, err, f.err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestTrimmedSilenceIsReported(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	// frames without audio data are silent
+	_ = makeMP3Files(fs, root, header44100, 5, validFileName1, validFileName2)
+	status := &bytes.Buffer{}
+
+	a := New(context.Background(), "", "test", "", status, fs, root, mp3binder.New(&testTagResolver{}), &testTagResolver{}, supportedLanguage, testUserConfigFile).(*application)
+	if err := a.command.ParseFlags([]string{"--" + flagTrimSilence}); err != nil {
+		panic(err)
+	}
+
+	err := a.args(a.command, []string{"."})
+	if assert.NoError(t, err) && assert.NoError(t, a.run(a.command, nil)) {
+		// not only with --verbose
+		assert.Contains(t, status.String(), "Trimmed silence of '"+validFileName1+"': '131ms' at the start and '0s' at the end")
+	}
+}
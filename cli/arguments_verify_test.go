@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/carolynvs/aferox"
+	"github.com/dmulholl/mp3lib"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+// makeBoundFile creates an output file of the input files with a Xing header of the frames and bytes.
+func makeBoundFile(fs afero.Fs, dir, name string, frames, bytesCount uint32, inputs ...string) string {
+	content := &bytes.Buffer{}
+	content.Write(mp3lib.NewXingHeader(frames, bytesCount).RawBytes)
+
+	for _, input := range inputs {
+		data, err := afero.ReadFile(fs, input)
+		if err != nil {
+			panic(err)
+		}
+
+		content.Write(data)
+	}
+
+	file := filepath.Join(dir, name)
+	if err := afero.WriteFile(fs, file, content.Bytes(), 0o644); err != nil {
+		panic(err)
+	}
+
+	return file
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+	root, fs := newTestFilesystem()
+	inputs := makeMP3Files(fs, root, header44100, 20, validFileName1)
+	inputs = append(inputs, makeMP3Files(fs, root, header48000, 10, validFileName2)...)
+
+	frameLength44100, frameLength48000 := uint32(144*128000/44100), uint32(144*128000/48000)
+	bytesCount := 20*frameLength44100 + 10*frameLength48000
+
+	bound := makeBoundFile(fs, root, "bound.mp3", 30, bytesCount, inputs...)
+	wrongHeader := makeBoundFile(fs, root, "header.mp3", 31, bytesCount, inputs...)
+
+	for _, f := range []struct {
+		title       string
+		args        []string
+		differences []string
+	}{
+		{title: "matches", args: []string{bound, validFileName1, validFileName2}},
+		{title: "wrong order", args: []string{bound, validFileName2, validFileName1}, differences: []string{
+			"- '" + validFileName2 + "' (input 1): frame 0 (0s) of the input file differs from frame 0 (0s) of the output file at byte 2",
+		}},
+		{title: "missing input", args: []string{bound, validFileName1}, differences: []string{
+			"- the output file has 10 frames (240ms) more than the input files",
+		}},
+		{title: "additional input", args: []string{bound, validFileName1, validFileName2, validFileName1}, differences: []string{
+			"- '" + validFileName1 + "' (input 3): the output file ends at frame 30 (762ms), but the input file continues with frame 0 (0s)",
+		}},
+		{title: "wrong header", args: []string{wrongHeader, validFileName1, validFileName2}, differences: []string{
+			"- the Xing header counts 31 frames, the output file has 30 frames",
+		}},
+	} {
+		f := f // pin
+		t.Run(f.title, func(t *testing.T) {
+			t.Parallel()
+
+			status := &bytes.Buffer{}
+			a := newDefaultApplication(aferox.NewAferox(root, fs))
+			a.status = status
+
+			err := a.verify(nil, f.args)
+			if len(f.differences) == 0 {
+				if assert.NoError(t, err) {
+					assert.Contains(t, status.String(), "matches its 2 input files")
+				}
+				return
+			}
+
+			assert.ErrorIs(t, err, ErrVerificationFailed)
+			for _, d := range f.differences {
+				assert.Contains(t, status.String(), d)
+			}
+		})
+	}
+}
+
+func TestVerifyArgs(t *testing.T) {
+	t.Parallel()
+
+	a := newDefaultApplication(aferox.NewAferox(newTestFilesystem()))
+
+	assert.ErrorIs(t, a.verifyArgs(nil, []string{"bound.mp3"}), ErrNoInput)
+	assert.NoError(t, a.verifyArgs(nil, []string{"bound.mp3", validFileName1}))
+}
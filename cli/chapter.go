@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+const (
+	chapterTemplateName = "chapter"
+
+	templateKeyIndex    = "index"
+	templateKeyFilename = "filename"
+	templateKeyName     = "name"
+	templateKeyDuration = "duration"
+)
+
+// leadingTrackNumber matches track numbers in front of a filename (e.g. '01 - ', '1-02. ', '3_').
+var leadingTrackNumber = regexp.MustCompile(`^\s*\d{1,3}(?:[-.]\d{1,3})?(?:\s*[-._)]\s*|\s+)`)
+
+// stripTrackNumber removes a leading track number from a name (e.g. '01 - Intro' becomes 'Intro').
+func stripTrackNumber(name string) string {
+	stripped := leadingTrackNumber.ReplaceAllString(name, "")
+	if strings.TrimSpace(stripped) == "" {
+		return name
+	}
+
+	return stripped
+}
+
+// stripExtension removes the file extension from a filename.
+func stripExtension(fileName string) string {
+	return strings.TrimSuffix(fileName, filepath.Ext(fileName))
+}
+
+// clock formats a duration as 'h:mm:ss' or 'm:ss' for durations shorter than an hour.
+func clock(d time.Duration) string {
+	d = d.Round(time.Second)
+	h := d / time.Hour
+	d -= h * time.Hour
+	m := d / time.Minute
+	d -= m * time.Minute
+	s := d / time.Second
+
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+
+	return fmt.Sprintf("%d:%02d", m, s)
+}
+
+// chapterTemplateFuncs returns the helper functions available in chapter title templates.
+func chapterTemplateFuncs(language language.Tag) template.FuncMap {
+	return template.FuncMap{
+		"notrack": stripTrackNumber,
+		"noext":   stripExtension,
+		"title":   cases.Title(language).String,
+		"upper":   cases.Upper(language).String,
+		"lower":   cases.Lower(language).String,
+		"trim":    strings.TrimSpace,
+		"replace": func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"default": func(fallback, s string) string {
+			if strings.TrimSpace(s) == "" {
+				return fallback
+			}
+
+			return s
+		},
+	}
+}
+
+// parseChapterTitleTemplate parses a chapter title template (e.g. '{{.index}}. {{.TIT2}}').
+func parseChapterTitleTemplate(language language.Tag, text string) (*template.Template, error) {
+	t, err := template.New(chapterTemplateName).
+		Option("missingkey=zero").
+		Funcs(chapterTemplateFuncs(language)).
+		Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("chapter title '%s': %v: %w", text, err, ErrInvalidTemplate)
+	}
+
+	return t, nil
+}
+
+// renderChapterTitle executes the chapter title template with the tags of an input file
+// and the information about the file (e.g. name, index and duration).
+func renderChapterTitle(t *template.Template, tags map[string]string, mediaFile string, chapterIndex int, duration time.Duration) (string, error) {
+	data := make(map[string]string, len(tags)+4)
+	for k, v := range tags {
+		data[k] = v
+	}
+
+	fileName := filepath.Base(mediaFile)
+
+	data[templateKeyIndex] = strconv.Itoa(chapterIndex)
+	data[templateKeyFilename] = fileName
+	data[templateKeyName] = stripExtension(fileName)
+	data[templateKeyDuration] = clock(duration)
+
+	b := &strings.Builder{}
+	if err := t.Execute(b, data); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(b.String()), nil
+}
@@ -5,12 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
+	"text/template"
+	"time"
 
-	"github.com/crra/mp3binder/slice"
+	"github.com/crra/mp3binder/mp3binder"
 
 	"github.com/carolynvs/aferox"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"golang.org/x/text/language"
 )
 
@@ -22,48 +26,129 @@ var (
 	ErrOutputFileExists    = errors.New("output file is already existing")
 	ErrInvalidIndex        = errors.New("the provided index is invalid")
 	ErrTagNonStandard      = errors.New("non-standard tag")
+	ErrTagInvalid          = errors.New("invalid tag")
+	ErrTagInvalidValue     = errors.New("invalid tag value")
 	ErrUnsupportedLanguage = errors.New("unsupported language")
 	ErrNoTagsInTemplate    = errors.New("no tags in template")
+	ErrInvalidTemplate     = errors.New("invalid template")
+	ErrInvalidStrategy     = errors.New("invalid merge strategy")
+	ErrInvalidCoverOption  = errors.New("invalid cover option")
+	ErrInvalidPicture      = errors.New("invalid picture")
+	ErrInvalidDiscovery    = errors.New("invalid discovery order")
+	ErrInvalidConfig       = errors.New("invalid config")
+	ErrInvalidJob          = errors.New("invalid job")
+	ErrInvalidGap          = errors.New("invalid gap")
+	ErrInvalidMismatch     = errors.New("invalid interlace mismatch option")
+	ErrInterlaceMismatch   = errors.New("interlace file does not match the input files")
+	ErrInvalidThreshold    = errors.New("invalid silence threshold")
+	ErrInvalidRange        = errors.New("invalid time range")
+	ErrInvalidOnCorrupt    = errors.New("invalid corruption handling")
+	ErrCorruptInput        = errors.New("input file is corrupt")
+	ErrVerificationFailed  = errors.New("output file does not match its input files")
 )
 
 const (
 	flagNoDiscovery   = "nodiscovery"
+	flagDiscovery     = "discovery-order"
 	flagNoChapters    = "nochapters"
+	flagChapterTitle  = "chapter-title"
+	flagChapterArt    = "chapter-artwork"
 	flagCover         = "cover"
+	flagCoverMaxSize  = "cover-max-size"
+	flagCoverFormat   = "cover-format"
+	flagPicture       = "picture"
+	flagExtractCover  = "extract-cover"
 	flagVerbose       = "verbose"
 	flagOverwrite     = "force"
 	flagInterlaceFile = "interlace"
+	flagGap           = "gap"
+	flagMismatch      = "interlace-mismatch"
+	flagTrimSilence   = "trim-silence"
+	flagTrimThreshold = "trim-threshold"
+	flagNormalize     = "normalize"
+	flagOnCorrupt     = "on-corrupt"
+	flagVerifyCRC     = "verify-crc"
+	flagDropBadFrames = "drop-bad-frames"
+	flagFolderSpacer  = "folder-spacer"
+	flagIntro         = "intro"
+	flagOutro         = "outro"
 	flagOutputFile    = "output"
 	flagInputFile     = "input"
 	flagApplyTags     = "tapply"
+	flagTagsFile      = "tags-file"
 	flagCopyTags      = "tcopy"
+	flagMergeTags     = "tmerge"
+	flagStrict        = "strict"
 	flagLanguageStr   = "lang"
+	flagJSON          = "json"
 )
 
 var (
 	outputFileExtension = ".mp3"
 	mediaFileExtensions = []string{".mp3"}
-	coverFileExtensions = []string{".jpg", ".jpeg", ".png"}
+	coverFileExtensions = []string{".jpg", ".jpeg", ".png", ".webp", ".gif", ".bmp"}
 	coverFileNames      = []string{"cover", "folder", "album"}
-	coverFiles          = slice.ConcatStr(coverFileNames, coverFileExtensions)
 	interlaceFiles      = []string{"interlace.mp3", "_interlace.mp3"}
 
 	rootDirectoryName = "root" + outputFileExtension
 )
 
-const tagTitle = "TIT2"
+// frames quieter than the threshold in dB below the median loudness of the file are silent
+const defaultTrimThreshold = -60
+
+const (
+	tagTitle = "TIT2"
+
+	// id3v2.4 separates multiple values of a text frame with a null character
+	multipleValuesSeparator = "\x00"
+	// long texts (e.g. comments or lyrics) are joined line by line
+	multipleLinesSeparator = "\n"
+	// long texts starting with '@' are read from a file (e.g. 'USLT[eng]=@lyrics.txt')
+	fileReferencePrefix = "@"
+)
+
+const (
+	// special characters of glob patterns in input files (e.g. 'cd1/*.mp3')
+	globCharacters = "*?["
+	// scheme of file URLs in playlists (e.g. 'file:///music/01.mp3')
+	fileURLScheme = "file"
+)
+
+const (
+	mergeAllTags      = "*"
+	mergeSeparator    = "\x00" // separates multiple values of a text frame (id3v2.4)
+	mergeFirst        = "first"
+	mergeMostCommon   = "common"
+	mergeConcatenated = "concat"
+)
+
+var mergeStrategies = map[string]mp3binder.MergeStrategy{
+	mergeFirst:        mp3binder.MergeFirst,
+	mergeMostCommon:   mp3binder.MergeMostCommon,
+	mergeConcatenated: mp3binder.MergeConcat,
+}
 
 type statusPrinter interface {
 	language(language string)
 	listMediaFilesAfterInterlace(mediaFiles []string)
 	listInputFiles(mediaFiles []string, outputFile string)
+	discoveredFile(fileType, file, dir string)
+	configFile(file string)
+	jobFile(file string)
 	coverFile(file string)
+	pictureFile(description, file string)
 	interlaceFile(file string)
+	cutFile(file string, cut timeRange)
+	gap(gap time.Duration)
+	interlaceReplaced(file string, silence time.Duration)
+	spacerFile(kind, file string)
 	copyTagsFrom(file string)
+	mergeTags(strategies map[string]mp3binder.MergeStrategy)
 	tagsToApply(tags map[string]string, tagResolver tagResolver)
 
 	actionObserver(stage, action string)
 	newBindObserver(mediaFiles []string) func(index int)
+	newNormalizeObserver(mediaFiles []string) func(index int, adjustment float64)
 	newTagCopyObserver(copyFilename string) func(tag, value string, err error)
 	newTagObserver(tags map[string]string) func(tag, value string, err error)
 }
@@ -80,6 +165,8 @@ type binder interface {
 
 type tagResolver interface {
 	DescriptionFor(string) (string, error)
+	Validate(key, value string) error
+	Normalize(key, value string) string
 }
 
 type application struct {
@@ -95,20 +182,49 @@ type application struct {
 
 	parent context.Context
 
+	userConfigFile    string
+	discoveryNames    discoveryNames
 	noDiscovery       bool
+	discoveryOrder    string
 	noChapters        bool
+	chapterTitle      string
+	chapterTemplate   *template.Template
+	chapterArtwork    bool
 	coverFile         string
 	coverFileMimeType string
+	coverMaxSize      int
+	coverFormat       string
+	pictureArgs       []string
+	pictures          []picture
+	extractCover      string
 	verbose           bool
 	overwrite         bool
 	interlaceFile     string
+	gap               time.Duration
+	interlaceMismatch string
+	trimSilence       bool
+	trimThreshold     float64
+	normalize         bool
+	onCorrupt         string
+	verifyCRC         bool
+	dropBadFrames     bool
+	folderSpacer      string
+	introFile         string
+	outroFile         string
 	outputPath        string
 	inputFile         string
 	applyTags         string
+	tagsFile          string
 	languageStr       string
 	language          language.Tag
 	copyTagsFromIndex int // NOTE: starts on '1' rathen than '0'
+	mergeTags         string
+	mergeStrategies   map[string]mp3binder.MergeStrategy
+	strict            bool
+	inspectJSON       bool
+	job               *job
 	mediaFiles        []string
+	entries           []entry
 	tags              map[string]string
 
 	command *cobra.Command
@@ -122,6 +238,8 @@ func (a *application) Execute() error {
 type mediaFile struct {
 	path          string
 	explicitlySet bool
+	// the part of the file to bind (e.g. 'intro.mp3@0:05-')
+	cut timeRange
 }
 
 func (m mediaFile) String() string {
@@ -134,7 +252,7 @@ const (
 	defaultTrackNumber = "1"
 )
 
-func New(parent context.Context, url, name, version string, status io.Writer, fs afero.Fs, cwd string, binder binder, tagResolver tagResolver, userLocale string) Service {
+func New(parent context.Context, url, name, version string, status io.Writer, fs afero.Fs, cwd string, binder binder, tagResolver tagResolver, userLocale, userConfigFile string) Service {
 	app := &application{
 		parent:      parent,
 		name:        name,
@@ -152,7 +270,13 @@ func New(parent context.Context, url, name, version string, status io.Writer, fs
 			tagEncoderSoftware: fmt.Sprintf("%s, %s", url, version),
 			tagIdTrack:         defaultTrackNumber,
 		},
-		languageStr: userLocale,
+		languageStr:    userLocale,
+		discoveryOrder: defaultDiscoveryOrder,
+		userConfigFile: userConfigFile,
+
+		interlaceMismatch: defaultInterlaceMismatch,
+		trimThreshold:     defaultTrimThreshold,
+		onCorrupt:         defaultOnCorrupt,
 	}
 
 	cmd := &cobra.Command{
@@ -169,23 +293,96 @@ func New(parent context.Context, url, name, version string, status io.Writer, fs
 		RunE: app.run,
 	}
 
+	cmd.CompletionOptions.DisableDefaultCmd = true
+	cmd.Flags().SortFlags = false
+
+	runCmd := &cobra.Command{
+		Use:   "run [folder]",
+		Short: fmt.Sprintf("binds the files described by the job file '%s' in the folder", jobFileName),
+		Long:  fmt.Sprintf("Binds the files described by the job file '%s' in the folder (defaults to the current directory).\nThe job file lists the input files with their chapter titles and interlace files, the tags, the cover and the output file.\nExplicitly set flags have priority over the job file.", jobFileName),
+
+		SilenceErrors: true,
+		SilenceUsage:  true,
+
+		Args: app.runArgs,
+		RunE: app.run,
+	}
+	runCmd.Flags().SortFlags = false
+	cmd.AddCommand(runCmd)
+
+	inspectCmd := &cobra.Command{
+		Use:   "inspect file.mp3...",
+		Short: "prints the stream and tag information of mp3 files",
+		Long:  "Prints the stream parameters, the duration, the Xing, VBRI or LAME header,\nthe id3v2 tags, the pictures and the chapters of mp3 files.",
+
+		SilenceErrors: true,
+		SilenceUsage:  true,
+
+		Args: app.inspectArgs,
+		RunE: app.inspect,
+	}
+	inspectCmd.Flags().BoolVar(&app.inspectJSON, flagJSON, app.inspectJSON, "prints the information as JSON")
+	cmd.AddCommand(inspectCmd)
+
+	verifyCmd := &cobra.Command{
+		Use:   "verify output.mp3 file1.mp3 file2.mp3",
+		Short: "verifies that the output file is bound from the input files",
+		Long:  "Verifies that the audio frames of the output file are identical to the frames of the input files,\nthat the chapters start and end with the input files and that the Xing header counts the frames and bytes.\nExits with an error and prints the differences if the output file does not match.",
+
+		SilenceErrors: true,
+		SilenceUsage:  true,
+
+		Args: app.verifyArgs,
+		RunE: app.verify,
+	}
+	cmd.AddCommand(verifyCmd)
+
 	cmd.SetOutput(status)
 	app.command = cmd
 
-	f := cmd.Flags()
-	f.SortFlags = false // prefer the order defined by the code
+	// the verbose output is shared by all commands
+	cmd.PersistentFlags().BoolVar(&app.verbose, flagVerbose, app.verbose, "prints verbose information for each processing step")
 
-	f.BoolVar(&app.noDiscovery, flagNoDiscovery, app.noDiscovery, "no discovery for well-known files (e.g. cover.jpg)")
-	f.BoolVar(&app.noChapters, flagNoChapters, app.noChapters, "does not write chapters for bounded files")
-	f.StringVar(&app.coverFile, flagCover, app.coverFile, "use image file as artwork")
-	f.BoolVar(&app.verbose, flagVerbose, app.verbose, "prints verbose information for each processing step")
-	f.BoolVar(&app.overwrite, flagOverwrite, app.overwrite, "overwrite an existing output file")
-	f.StringVar(&app.interlaceFile, flagInterlaceFile, app.interlaceFile, "interlace a spacer file (e.g. silence) between each input file")
-	f.StringVar(&app.outputPath, flagOutputFile, app.outputPath, "output filepath. Defaults to name of the folder of the first file provided")
-	f.StringVar(&app.inputFile, flagInputFile, app.inputFile, "file containing a list of input files")
-	f.StringVar(&app.applyTags, flagApplyTags, app.applyTags, "apply id3v2 tags to output file.\nTakes the format: 'key1=\"value\",key2=\"value\"'.\nKeys should be from https://id3.org/id3v2.3.0#Declared_ID3v2_frames")
-	f.IntVar(&app.copyTagsFromIndex, flagCopyTags, app.copyTagsFromIndex, "copy the ID3 metadata tag from the n-th input file, starting with 1")
-	f.StringVar(&app.languageStr, flagLanguageStr, app.languageStr, "ISO-639 language string used during string manipulation\n(e.g. uppercasing non-english languages)")
+	// the flags of the binding are shared with the 'run' command
+	app.addBindFlags(cmd.Flags())
+	app.addBindFlags(runCmd.Flags())
 
 	return app
 }
+
+// addBindFlags adds the flags of the binding to the flag set.
+func (a *application) addBindFlags(f *pflag.FlagSet) {
+	f.SortFlags = false // prefer the order defined by the code
+
+	f.BoolVar(&a.noDiscovery, flagNoDiscovery, a.noDiscovery, "no discovery for well-known files (e.g. cover.jpg)")
+	f.StringVar(&a.discoveryOrder, flagDiscovery, a.discoveryOrder, "directories searched for well-known files, the first directory containing one wins.\nLocations: "+strings.Join(discoveryLocations, ", "))
+	f.BoolVar(&a.noChapters, flagNoChapters, a.noChapters, "does not write chapters for bounded files")
+	f.StringVar(&a.chapterTitle, flagChapterTitle, a.chapterTitle, "template for the chapter titles (e.g. '{{.index}}. {{.TIT2}} - {{.TPE1}}').\nProvides the tags of the file, 'index', 'filename', 'name' and 'duration'\nand the helpers: notrack, noext, title, upper, lower, trim, replace, default")
+	f.BoolVar(&a.chapterArtwork, flagChapterArt, a.chapterArtwork, "embeds the cover and the link (WXXX) of each file in its chapter.\nImages identical to the cover or the previous chapter are not repeated")
+	f.StringVar(&a.coverFile, flagCover, a.coverFile, "use image file as artwork")
+	f.IntVar(&a.coverMaxSize, flagCoverMaxSize, a.coverMaxSize, "limits the longer side of the cover to the size in pixels (e.g. 600). Smaller covers are not enlarged")
+	f.StringVar(&a.coverFormat, flagCoverFormat, a.coverFormat, "converts the cover to the format: 'jpeg' (baseline) or 'png'. Converted covers are stripped of metadata (e.g. EXIF)")
+	f.StringArrayVar(&a.pictureArgs, flagPicture, a.pictureArgs, "attach an image with a picture type (e.g. 'back=back.jpg'), can be repeated.\nTypes: "+strings.Join(pictureKindNames(), ", "))
+	f.StringVar(&a.extractCover, flagExtractCover, a.extractCover, "saves the cover of the output file to the path (e.g. the cover embedded in the first input file)")
+	f.BoolVar(&a.overwrite, flagOverwrite, a.overwrite, "overwrite an existing output file")
+	f.StringVar(&a.interlaceFile, flagInterlaceFile, a.interlaceFile, "interlace a spacer file (e.g. silence) between each input file")
+	f.StringVar(&a.interlaceMismatch, flagMismatch, a.interlaceMismatch, "handling of an interlace file with another format (e.g. sampling rate) than the input files.\n'warn', 'silence' (replaces it with generated silence of the same duration) or 'error'")
+	f.DurationVar(&a.gap, flagGap, a.gap, "put silence of the duration (e.g. '2s') between each input file.\nThe silence matches the format (e.g. sampling rate and bitrate) of the previous input file")
+	f.BoolVar(&a.trimSilence, flagTrimSilence, a.trimSilence, "drops the silent frames at the start and the end of each input file")
+	f.Float64Var(&a.trimThreshold, flagTrimThreshold, a.trimThreshold, "frames quieter than the threshold in dB below the median loudness of the file are silent,\nestimated from the side information of the frames. Frames without audio data are always silent")
+	f.BoolVar(&a.normalize, flagNormalize, a.normalize, "adjusts the volume of the input files to their average loudness in steps of 1.5 dB without re-encoding.\nWrites the estimated ReplayGain ('TXXX[REPLAYGAIN_TRACK_GAIN]' and 'TXXX[REPLAYGAIN_TRACK_PEAK]')")
+	f.StringVar(&a.onCorrupt, flagOnCorrupt, a.onCorrupt, "handling of input files with corrupt regions (e.g. garbage or truncated frames), which are skipped.\n'skip', 'warn' or 'abort' (e.g. to reject broken rips)")
+	f.BoolVar(&a.verifyCRC, flagVerifyCRC, a.verifyCRC, "verifies the CRC of the frames protected by one and reports the frames with a wrong CRC")
+	f.BoolVar(&a.dropBadFrames, flagDropBadFrames, a.dropBadFrames, "drops the frames with a wrong CRC, implies --"+flagVerifyCRC)
+	f.StringVar(&a.folderSpacer, flagFolderSpacer, a.folderSpacer, "put a spacer file between input files from different folders instead of the interlace file (e.g. a longer pause between discs)")
+	f.StringVar(&a.introFile, flagIntro, a.introFile, "put a spacer file (e.g. a jingle) before the first input file")
+	f.StringVar(&a.outroFile, flagOutro, a.outroFile, "put a spacer file after the last input file")
+	f.StringVar(&a.outputPath, flagOutputFile, a.outputPath, "output filepath. Defaults to name of the folder of the first file provided")
+	f.StringVar(&a.inputFile, flagInputFile, a.inputFile, "file containing a list of input files (one path or glob pattern per line, relative to the file)\nor a M3U, M3U8 or PLS playlist, the titles of a playlist are used as chapter titles")
+	f.StringVar(&a.applyTags, flagApplyTags, a.applyTags, "apply id3v2 tags to output file.\nTakes the format: 'key1=\"value\",key2=\"value\"'.\nKeys should be from https://id3.org/id3v2.3.0#Declared_ID3v2_frames.\nFrames that can be present multiple times take qualifiers in brackets: 'COMM[eng:description]', 'USLT[eng]=@lyrics.txt',\n'TXXX[description]', 'WXXX[description]' or 'POPM[email]=rating/counter'")
+	f.StringVar(&a.tagsFile, flagTagsFile, a.tagsFile, "apply id3v2 tags from a JSON, YAML or 'KEY=value' per line file.\nComments take the key 'COMM:language:description', user defined texts 'TXXX:description'")
+	f.IntVar(&a.copyTagsFromIndex, flagCopyTags, a.copyTagsFromIndex, "copy the ID3 metadata tag from the n-th input file, starting with 1")
+	f.StringVar(&a.mergeTags, flagMergeTags, a.mergeTags, "merge the ID3 metadata tags of all input files with a strategy per tag.\nTakes the format: 'TALB=common,TCOM=concat,*=first'.\nStrategies: 'common' (most common value), 'concat' (distinct values), 'first' (first non-empty value).\nCan't be combined with --"+flagCopyTags)
+	f.BoolVar(&a.strict, flagStrict, a.strict, "rejects non-standard tags and tag values that violate the rules of their frame\n(e.g. 'TRCK=3/12', 'TDRC=2021-05-01', 'TLAN=eng', 'TCON=Rock') instead of warning")
+	f.StringVar(&a.languageStr, flagLanguageStr, a.languageStr, "ISO-639 language string used during string manipulation\n(e.g. uppercasing non-english languages)")
+}
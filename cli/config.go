@@ -0,0 +1,223 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	fs2 "io/fs"
+	"path/filepath"
+	"sort"
+
+	"github.com/carolynvs/aferox"
+	"github.com/crra/mp3binder/slice"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// name of the directory in the user config directory (e.g. '~/.config/mp3binder')
+	configDirName      = "mp3binder"
+	userConfigFileName = "config.yaml"
+	// config file in the folder of the input files
+	folderConfigFileName = ".mp3binder.yaml"
+	// key of the section that extends the names of the well-known files
+	configDiscoveryKey = "discovery"
+)
+
+// flags that can't be set by a config file
+var configExcludedFlags = []string{"help", "version"}
+
+// config holds the defaults for the flags and the extended discovery names of a config file.
+type config struct {
+	file      string
+	flags     map[string][]string
+	discovery discoveryNames
+}
+
+// UserConfigFile returns the path of the user config file in the config directory of the user
+// (e.g. '$XDG_CONFIG_HOME').
+func UserConfigFile(userConfigDir string) string {
+	return filepath.Join(userConfigDir, configDirName, userConfigFileName)
+}
+
+// readConfig reads a config file. A missing config file is not an error and returns nil.
+func readConfig(fs aferox.Aferox, file string) (*config, error) {
+	f, err := fs.Open(file)
+	if err != nil {
+		if errors.Is(err, fs2.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+	defer f.Close()
+
+	c, err := decodeConfig(f)
+	if err != nil {
+		return nil, fmt.Errorf("config file '%s': %w", file, err)
+	}
+	c.file = file
+
+	return c, nil
+}
+
+// decodeConfig reads the flags by their name (scalars or lists of scalars) and the discovery section.
+func decodeConfig(r io.Reader) (*config, error) {
+	raw := make(map[string]yaml.Node)
+	if err := yaml.NewDecoder(r).Decode(&raw); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%v: %w", err, ErrInvalidConfig)
+	}
+
+	c := &config{flags: make(map[string][]string, len(raw))}
+	for k, node := range raw {
+		if k == configDiscoveryKey {
+			var names discoveryNames
+			if err := node.Decode(&names); err != nil {
+				return nil, fmt.Errorf("%s: %v: %w", k, err, ErrInvalidConfig)
+			}
+
+			normalized, err := names.normalize()
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", k, err)
+			}
+			c.discovery = normalized
+
+			continue
+		}
+
+		switch node.Kind {
+		case yaml.ScalarNode:
+			c.flags[k] = []string{configScalar(&node)}
+		case yaml.SequenceNode:
+			values := []string{}
+			for _, e := range node.Content {
+				if e.Kind != yaml.ScalarNode {
+					return nil, fmt.Errorf("option '%s' has an unsupported value: %w", k, ErrInvalidConfig)
+				}
+
+				values = append(values, configScalar(e))
+			}
+			c.flags[k] = values
+		default:
+			return nil, fmt.Errorf("option '%s' has an unsupported value: %w", k, ErrInvalidConfig)
+		}
+	}
+
+	return c, nil
+}
+
+func configScalar(node *yaml.Node) string {
+	if node.Tag == "!!null" {
+		return ""
+	}
+
+	return node.Value
+}
+
+// apply sets the flags to the values of the config, except the explicitly set flags.
+func (c *config) apply(flags *pflag.FlagSet, explicit map[string]bool) error {
+	names := make([]string, 0, len(c.flags))
+	for name := range c.flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		values := c.flags[name]
+
+		flag := flags.Lookup(name)
+		if flag == nil || slice.Contains(configExcludedFlags, name) {
+			return fmt.Errorf("config file '%s': unknown option '%s': %w", c.file, name, ErrInvalidConfig)
+		}
+
+		if explicit[name] {
+			continue
+		}
+
+		// lists (e.g. pictures) of a config with a higher precedence replace the list
+		if list, ok := flag.Value.(pflag.SliceValue); ok {
+			if err := list.Replace(values); err != nil {
+				return fmt.Errorf("config file '%s': option '%s': %v: %w", c.file, name, err, ErrInvalidConfig)
+			}
+
+			continue
+		}
+
+		if len(values) != 1 {
+			return fmt.Errorf("config file '%s': option '%s' takes a single value: %w", c.file, name, ErrInvalidConfig)
+		}
+
+		if err := flags.Set(name, values[0]); err != nil {
+			return fmt.Errorf("config file '%s': option '%s': %v: %w", c.file, name, err, ErrInvalidConfig)
+		}
+	}
+
+	return nil
+}
+
+// configFolder returns the folder of the first argument or the working directory.
+func configFolder(fs aferox.Aferox, args []string) string {
+	if len(args) == 0 {
+		return fs.Getwd()
+	}
+
+	first := fs.Abs(args[0])
+	info, err := fs.Stat(first)
+	if err != nil {
+		return fs.Getwd()
+	}
+
+	if info.IsDir() {
+		return first
+	}
+
+	return filepath.Dir(first)
+}
+
+// explicitFlags returns the names of the flags set on the command line. It must be called before
+// the configs set the flags, which marks them as changed.
+func explicitFlags(flags *pflag.FlagSet) map[string]bool {
+	explicit := make(map[string]bool)
+	if flags != nil {
+		flags.Visit(func(f *pflag.Flag) { explicit[f.Name] = true })
+	}
+
+	return explicit
+}
+
+// applyConfigs applies the user config and the config in the folder of the first argument as defaults
+// for the flags that are not set explicitly. The folder config takes precedence over the user config.
+// Both extend the names of the well-known files.
+func (a *application) applyConfigs(flags *pflag.FlagSet, explicit map[string]bool, args []string) ([]string, error) {
+	var files []string
+	if a.userConfigFile != "" {
+		files = append(files, a.fs.Abs(a.userConfigFile))
+	}
+
+	if folderConfig := filepath.Join(configFolder(a.fs, args), folderConfigFileName); !slice.Contains(files, folderConfig) {
+		files = append(files, folderConfig)
+	}
+
+	var applied []string
+	for _, file := range files {
+		c, err := readConfig(a.fs, file)
+		if err != nil {
+			return nil, err
+		}
+
+		if c == nil {
+			continue
+		}
+
+		if flags != nil {
+			if err := c.apply(flags, explicit); err != nil {
+				return nil, err
+			}
+		}
+
+		a.discoveryNames = a.discoveryNames.extend(c.discovery)
+		applied = append(applied, file)
+	}
+
+	return applied, nil
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/crra/mp3binder/mp3binder"
+	"github.com/crra/mp3binder/slice"
+)
+
+const (
+	// handling of input files with corrupt regions (e.g. garbage or truncated frames)
+	corruptSkip  = "skip"
+	corruptWarn  = "warn"
+	corruptAbort = "abort"
+
+	defaultOnCorrupt = corruptWarn
+
+	// the number of corrupt regions (or frames) listed per input file
+	maxListedCorruptions = 5
+)
+
+var corruptHandlings = []string{corruptSkip, corruptWarn, corruptAbort}
+
+// parseOnCorrupt returns the handling of input files with corrupt regions.
+func parseOnCorrupt(onCorrupt string) (string, error) {
+	onCorrupt = strings.ToLower(strings.TrimSpace(onCorrupt))
+	if onCorrupt == "" {
+		return defaultOnCorrupt, nil
+	}
+
+	if !slice.Contains(corruptHandlings, onCorrupt) {
+		return "", fmt.Errorf("%s: unknown value '%s', supported: %s: %w", flagOnCorrupt, onCorrupt, strings.Join(corruptHandlings, ", "), ErrInvalidOnCorrupt)
+	}
+
+	return onCorrupt, nil
+}
+
+// corruptionFound handles the corrupt regions of the media file at the index, which are skipped
+// while binding.
+func (a *application) corruptionFound(index int, corruptions []mp3binder.Corruption) error {
+	var skipped int
+	for _, c := range corruptions {
+		skipped += c.Length
+	}
+
+	summary := fmt.Sprintf("'%s' has %d corrupt region(s) of %d bytes in total: %s",
+		a.mediaFiles[index], len(corruptions), skipped, listCorruptions(corruptions))
+
+	switch a.onCorrupt {
+	case corruptAbort:
+		return fmt.Errorf("%s: %w", summary, ErrCorruptInput)
+	case corruptWarn:
+		fmt.Fprintf(a.status, "! Warning: %s. The regions are skipped, use '--%s %s' to reject the file\n", summary, flagOnCorrupt, corruptAbort)
+	}
+
+	return nil
+}
+
+// listCorruptions describes the location of the corrupt regions (e.g. '417 bytes at byte 8192 (0:01)').
+func listCorruptions(corruptions []mp3binder.Corruption) string {
+	locations := make([]string, 0, maxListedCorruptions+1)
+	for i, c := range corruptions {
+		if i == maxListedCorruptions {
+			locations = append(locations, fmt.Sprintf("and %d more", len(corruptions)-i))
+			break
+		}
+
+		locations = append(locations, fmt.Sprintf("%d bytes at byte %d (%s)", c.Length, c.Offset, clock(c.Position)))
+	}
+
+	return strings.Join(locations, ", ")
+}
+
+// crcMismatchFound reports the frames of the media file at the index with a wrong CRC.
+func (a *application) crcMismatchFound(index int, positions []time.Duration) {
+	handling := "kept"
+	if a.dropBadFrames {
+		handling = "dropped"
+	}
+
+	locations := make([]string, 0, maxListedCorruptions+1)
+	for i, p := range positions {
+		if i == maxListedCorruptions {
+			locations = append(locations, fmt.Sprintf("and %d more", len(positions)-i))
+			break
+		}
+
+		locations = append(locations, p.Round(time.Millisecond).String())
+	}
+
+	fmt.Fprintf(a.status, "! Warning: '%s' has %d frame(s) with a wrong CRC at: %s. The frames are %s\n",
+		a.mediaFiles[index], len(positions), strings.Join(locations, ", "), handling)
+}
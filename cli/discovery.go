@@ -0,0 +1,248 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	fs2 "io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/carolynvs/aferox"
+	"github.com/crra/mp3binder/slice"
+)
+
+// Locations searched for well-known files (e.g. 'cover.jpg').
+const (
+	// the directories of the input files in the order of the input files
+	discoverInputs = "inputs"
+	// the directories of the input files starting with the last input file
+	discoverInputsReversed = "inputs-reversed"
+	// the directory of the output file
+	discoverOutput = "output"
+	// the working directory
+	discoverWorkdir = "workdir"
+
+	discoveryOrderSeparator = ","
+)
+
+var (
+	discoveryLocations    = []string{discoverInputs, discoverInputsReversed, discoverOutput, discoverWorkdir}
+	defaultDiscoveryOrder = strings.Join([]string{discoverInputs, discoverWorkdir}, discoveryOrderSeparator)
+)
+
+// discoveryNames extends the built-in names of the well-known files (e.g. by a config file).
+type discoveryNames struct {
+	// names of the cover (without extension)
+	Cover []string `yaml:"cover"`
+	// extensions of the cover and the pictures
+	CoverExtensions []string `yaml:"cover-extensions"`
+	// names of the interlace file (with extension)
+	Interlace []string `yaml:"interlace"`
+	// names of the pictures by type (without extension)
+	Pictures map[string][]string `yaml:"pictures"`
+}
+
+// normalize lowercases the names and checks the picture types.
+func (n discoveryNames) normalize() (discoveryNames, error) {
+	normalized := discoveryNames{
+		Cover:           slice.Map(n.Cover, normalizeName),
+		CoverExtensions: slice.Map(n.CoverExtensions, normalizeExtension),
+		Interlace:       slice.Map(n.Interlace, normalizeName),
+	}
+
+	for name, names := range n.Pictures {
+		kind, ok := pictureKindOf(name)
+		if !ok {
+			return discoveryNames{}, fmt.Errorf("unknown picture type '%s', supported: %s: %w", name, strings.Join(pictureKindNames(), ", "), ErrInvalidConfig)
+		}
+
+		if kind.name == pictureFront {
+			normalized.Cover = append(normalized.Cover, slice.Map(names, normalizeName)...)
+			continue
+		}
+
+		if normalized.Pictures == nil {
+			normalized.Pictures = make(map[string][]string)
+		}
+		normalized.Pictures[kind.name] = append(normalized.Pictures[kind.name], slice.Map(names, normalizeName)...)
+	}
+
+	return normalized, nil
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func normalizeExtension(extension string) string {
+	extension = normalizeName(extension)
+	if !strings.HasPrefix(extension, ".") {
+		extension = "." + extension
+	}
+
+	return extension
+}
+
+// extend returns the names extended by the names of the other.
+func (n discoveryNames) extend(other discoveryNames) discoveryNames {
+	extended := discoveryNames{
+		Cover:           append(append([]string{}, n.Cover...), other.Cover...),
+		CoverExtensions: append(append([]string{}, n.CoverExtensions...), other.CoverExtensions...),
+		Interlace:       append(append([]string{}, n.Interlace...), other.Interlace...),
+		Pictures:        make(map[string][]string, len(n.Pictures)+len(other.Pictures)),
+	}
+
+	for _, pictures := range []map[string][]string{n.Pictures, other.Pictures} {
+		for kind, names := range pictures {
+			extended.Pictures[kind] = append(extended.Pictures[kind], names...)
+		}
+	}
+
+	return extended
+}
+
+func (n discoveryNames) coverExtensions() []string {
+	return append(append([]string{}, coverFileExtensions...), n.CoverExtensions...)
+}
+
+func (n discoveryNames) coverFiles() []string {
+	return slice.ConcatStr(append(append([]string{}, coverFileNames...), n.Cover...), n.coverExtensions())
+}
+
+func (n discoveryNames) interlaceFiles() []string {
+	return append(append([]string{}, interlaceFiles...), n.Interlace...)
+}
+
+func (n discoveryNames) pictureFiles(kind pictureKind) []string {
+	return slice.ConcatStr(append(append([]string{}, kind.wellKnownNames...), n.Pictures[kind.name]...), n.coverExtensions())
+}
+
+// isAcceptedCoverFile returns true if the provided path points to a valid cover file.
+func (n discoveryNames) isAcceptedCoverFile(path string) bool {
+	return slice.Contains(n.coverExtensions(), strings.ToLower(filepath.Ext(path)))
+}
+
+// isAcceptedMediaFile indicates if a file is accepted for joining.
+func (n discoveryNames) isAcceptedMediaFile(path string, skipInterlaceFiles bool) bool {
+	// ignore the magic interlace files
+	if skipInterlaceFiles && slice.Contains(n.interlaceFiles(), strings.ToLower(filepath.Base(path))) {
+		return false
+	}
+
+	return slice.Contains(mediaFileExtensions, strings.ToLower(filepath.Ext(path)))
+}
+
+// discovery finds well-known files in a list of directories. The first directory containing
+// a well-known file wins.
+type discovery struct {
+	fs       aferox.Aferox
+	disabled bool
+	dirs     []string
+	names    discoveryNames
+	// observer is called for every discovered file with the directory it was found in
+	observer func(fileType, file, dir string)
+}
+
+// parseDiscoveryOrder parses the comma separated list of locations (e.g. 'inputs,workdir').
+func parseDiscoveryOrder(order string) ([]string, error) {
+	if strings.TrimSpace(order) == "" {
+		order = defaultDiscoveryOrder
+	}
+
+	var locations []string
+	for _, l := range strings.Split(order, discoveryOrderSeparator) {
+		l = strings.ToLower(strings.TrimSpace(l))
+		if !slice.Contains(discoveryLocations, l) {
+			return nil, fmt.Errorf("%s: unknown location '%s', supported: %s: %w", flagDiscovery, l, strings.Join(discoveryLocations, ", "), ErrInvalidDiscovery)
+		}
+
+		if slice.Contains(locations, l) {
+			return nil, fmt.Errorf("%s: the location '%s' is set multiple times: %w", flagDiscovery, l, ErrInvalidDiscovery)
+		}
+
+		locations = append(locations, l)
+	}
+
+	return locations, nil
+}
+
+// discoveryDirs returns the distinct directories for the locations in the order of precedence.
+func discoveryDirs(fs aferox.Aferox, locations []string, mediaFiles []string, outputPath string) []string {
+	var dirs []string
+	add := func(dir string) {
+		dir = fs.Abs(dir)
+		if !slice.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	for _, l := range locations {
+		switch l {
+		case discoverInputs:
+			for _, f := range mediaFiles {
+				add(filepath.Dir(f))
+			}
+		case discoverInputsReversed:
+			for i := len(mediaFiles) - 1; i >= 0; i-- {
+				add(filepath.Dir(mediaFiles[i]))
+			}
+		case discoverOutput:
+			add(filepath.Dir(outputPath))
+		case discoverWorkdir:
+			add(fs.Getwd())
+		}
+	}
+
+	return dirs
+}
+
+// file returns the explicitly set file or discovers a well-known file if not set.
+func (d discovery) file(file string, fileType string, accept func(string) bool, wellKnownFiles []string) (string, error) {
+	// not set and no discovery
+	if file == "" && d.disabled {
+		return "", nil
+	}
+
+	// explicitly set
+	if file != "" {
+		file = d.fs.Abs(file)
+
+		info, err := d.fs.Stat(file)
+		if err != nil {
+			if errors.Is(err, fs2.ErrNotExist) {
+				return "", fmt.Errorf("%s file: '%s': %w", fileType, file, ErrFileNotFound)
+			}
+
+			return "", err
+		}
+
+		if info.IsDir() {
+			return "", ErrInvalidFile
+		}
+
+		if !accept(file) {
+			return "", fmt.Errorf("%s file '%s': %w", fileType, info.Name(), ErrInvalidFile)
+		}
+
+		return file, nil
+	}
+
+	// discover
+	for _, dir := range d.dirs {
+		dirListing, err := d.fs.ReadDir(dir)
+		if err != nil {
+			return "", err
+		}
+
+		if found := slice.FirstEqual(slice.Map(dirListing, func(info fs2.FileInfo) string { return info.Name() }), wellKnownFiles, strings.ToLower); found != nil {
+			file = filepath.Join(dir, *found)
+			if d.observer != nil {
+				d.observer(fileType, file, dir)
+			}
+
+			return file, nil
+		}
+	}
+
+	return "", nil
+}
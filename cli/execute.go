@@ -1,16 +1,19 @@
 package cli
 
 import (
+	"bytes"
 	"fmt"
+	"image"
 	"io"
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 	"unicode"
 
+	"github.com/crra/mp3binder/image/cover"
 	"github.com/crra/mp3binder/io/rewindingreader"
 	"github.com/crra/mp3binder/mp3binder"
-	"github.com/crra/mp3binder/slice"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"golang.org/x/text/cases"
@@ -19,9 +22,12 @@ import (
 
 // run is the cobra way of running the application.
 func (a *application) run(c *cobra.Command, _ []string) error {
-	if a.interlaceFile != "" {
-		a.mediaFiles = slice.Interlace(a.mediaFiles, a.interlaceFile)
-		a.copyTagsFromIndex = slice.IndexAfterInterlace(len(a.mediaFiles), a.copyTagsFromIndex-1)
+	if len(a.entries) != len(a.mediaFiles) {
+		a.entries = make([]entry, len(a.mediaFiles))
+	}
+
+	if a.hasSpacers() {
+		a.insertSpacers()
 
 		a.statusPrinter.listMediaFilesAfterInterlace(a.mediaFiles)
 	}
@@ -89,6 +95,8 @@ func unCamel(s string) string {
 func openFilesOnce(fs afero.Fs, files []string) ([]io.Reader, func(), error) {
 	input := make([]io.Reader, len(files))
 	openedFiles := make(map[string]afero.File)
+	// a file that is bound multiple times (e.g. an interlace file) shares the rewinding reader
+	readers := make(map[string]io.Reader)
 
 	close := func() {
 		for name := range openedFiles {
@@ -99,8 +107,8 @@ func openFilesOnce(fs afero.Fs, files []string) ([]io.Reader, func(), error) {
 	}
 
 	for i, name := range files {
-		if f, ok := openedFiles[name]; ok {
-			input[i] = f
+		if r, ok := readers[name]; ok {
+			input[i] = r
 			continue
 		}
 
@@ -112,6 +120,7 @@ func openFilesOnce(fs afero.Fs, files []string) ([]io.Reader, func(), error) {
 
 		input[i] = rewindingreader.New(f)
 		openedFiles[name] = f
+		readers[name] = input[i]
 	}
 
 	return input, close, nil
@@ -122,14 +131,40 @@ func titleFromString(language language.Tag, fileName string) string {
 	return cases.Title(language).String(strings.TrimSuffix(fileName, path.Ext(fileName)))
 }
 
+// coverOptions returns the processing options for the cover and all other pictures.
+func (a *application) coverOptions() cover.Options {
+	return cover.Options{MaxSize: a.coverMaxSize, Format: a.coverFormat}
+}
+
+// processEmbeddedCover processes a picture embedded in an input file. The MIME type of the
+// frame is often wrong (e.g. 'image/jpg' or a PNG labelled as JPEG), it is detected from the
+// content instead. The picture must be a complete image.
+func processEmbeddedCover(r io.Reader, o cover.Options) ([]byte, string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, "", err
+	}
+
+	mimeType, err := cover.DetectMimeType(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+
+	if _, _, err := image.Decode(bytes.NewReader(data)); err != nil {
+		return nil, "", fmt.Errorf("%v: %w", err, cover.ErrInvalidImage)
+	}
+
+	return cover.Process(bytes.NewReader(data), mimeType, o)
+}
+
 // bindingOptions returns configuration options for the bind method based on the user input.
 func (a *application) bindingOptions() ([]any, func(), error) {
 	options := []any{}
 
-	var coverFile io.Closer
+	var pictureFiles []io.Closer
 	closer := func() {
-		if coverFile != nil {
-			coverFile.Close()
+		for _, f := range pictureFiles {
+			f.Close()
 		}
 	}
 
@@ -142,21 +177,73 @@ func (a *application) bindingOptions() ([]any, func(), error) {
 		)
 	}
 
+	// durations of the input files that don't match their headers or tags (e.g. truncated files)
+	options = append(options, mp3binder.VerifyDurations(func(index int, source string, expected, computed time.Duration) {
+		fmt.Fprintf(a.status, "! Warning: the duration of '%s' is '%s', but its %s states '%s'. The file may be truncated or corrupt\n",
+			a.mediaFiles[index], computed.Round(time.Millisecond), source, expected.Round(time.Millisecond))
+	}))
+
+	// regions of the input files skipped while binding (e.g. garbage or truncated frames)
+	options = append(options, mp3binder.DetectCorruption(a.corruptionFound))
+
+	// frames with a wrong CRC (e.g. damaged during a transfer)
+	if a.verifyCRC || a.dropBadFrames {
+		options = append(options, mp3binder.VerifyCRC(a.dropBadFrames, a.crcMismatchFound))
+	}
+
 	// copy tags
 	if a.copyTagsFromIndex > 0 {
 		options = append(options, mp3binder.TagCopyVisitor(
 			a.statusPrinter.newTagCopyObserver(a.mediaFiles[a.copyTagsFromIndex-1])))
+	} else if len(a.mergeStrategies) > 0 {
+		options = append(options, mp3binder.TagCopyVisitor(a.statusPrinter.newTagCopyObserver("")))
+	}
+
+	// silence between the input files, spacers (e.g. an interlace file) replace it
+	if a.hasGaps() {
+		options = append(options, mp3binder.Gap(a.gapAfter))
+	}
+
+	// parts of the input files
+	if a.hasCuts() {
+		options = append(options, mp3binder.Cut(func(index int) (time.Duration, time.Duration) {
+			cut := a.entries[index].cut
+			return cut.start, cut.end
+		}))
+	}
+
+	// silence at the start and the end of the input files, spacers are kept
+	if a.trimSilence {
+		options = append(options, mp3binder.TrimSilence(a.trimThreshold,
+			func(index int) bool { return !a.isSpacer(index) },
+			func(index int, leading, trailing time.Duration) {
+				// not only with --verbose, the chapters are shortened accordingly
+				if leading > 0 || trailing > 0 {
+					fmt.Fprintf(a.status, "Trimmed silence of '%s': '%s' at the start and '%s' at the end\n",
+						filepath.Base(a.mediaFiles[index]), leading.Round(time.Millisecond), trailing.Round(time.Millisecond))
+				}
+			}))
+	}
+
+	// volume of the input files, spacers are kept
+	if a.normalize {
+		options = append(options, mp3binder.Normalize(
+			func(index int) bool { return !a.isSpacer(index) },
+			a.statusPrinter.newNormalizeObserver(a.mediaFiles)))
 	}
 
 	// chapter
 	if !a.noChapters {
 		// contains titles for chapters filled by the id3v2 title of the input file
 		chapterTitles := make([]string, len(a.mediaFiles))
+		// contains the tags of each media file for the chapter title template
+		chapterTags := make([]map[string]string, len(a.mediaFiles))
 
 		// Extract the title tag from each media file by collecting the titles
 		// from the file's metadata.
 		options = append(options, mp3binder.MetadataVisitor(func(index int, tags map[string]string) {
 			chapterTitles[index] = tags[tagTitle]
+			chapterTags[index] = tags
 
 			if chapterTitles[index] == "" {
 				chapterTitles[index] = titleFromString(a.language, a.mediaFiles[index])
@@ -164,25 +251,81 @@ func (a *application) bindingOptions() ([]any, func(), error) {
 		}))
 
 		// Enable chapters and register a function that provides the name of the chapter.
-		options = append(options, mp3binder.Chapters(func(index, chapterIndex int) (bool, string) {
+		options = append(options, mp3binder.Chapters(func(index, chapterIndex int, duration time.Duration) (bool, string) {
 			chapterTitle := fmt.Sprintf("Chapter %d", chapterIndex)
 
 			if title := chapterTitles[index]; title != "" {
 				chapterTitle = title
 			}
 
-			return (a.interlaceFile == "") || (a.mediaFiles[index] != a.interlaceFile), chapterTitle
+			if title := a.entries[index].chapterTitle; title != "" {
+				return !a.isSpacer(index), title
+			}
+
+			if a.chapterTemplate != nil {
+				// fall back to the default title if the template can't be rendered
+				if title, err := renderChapterTitle(a.chapterTemplate, chapterTags[index], a.mediaFiles[index], chapterIndex, duration); err == nil && title != "" {
+					chapterTitle = title
+				}
+			}
+
+			return !a.isSpacer(index), chapterTitle
 		}))
+
+		if a.chapterArtwork {
+			options = append(options, mp3binder.ChapterArtwork())
+		}
 	}
 
 	// cover file
 	if a.coverFile != "" {
-		coverFile, err := a.fs.Open(a.coverFile)
+		f, err := a.fs.Open(a.coverFile)
+		if err != nil {
+			return []any{}, closer, err
+		}
+		pictureFiles = append(pictureFiles, f)
+
+		picture, mimeType, err := cover.Process(f, a.coverFileMimeType, a.coverOptions())
+		if err != nil {
+			return []any{}, closer, fmt.Errorf("cover file: '%s': %w", a.coverFile, err)
+		}
+
+		options = append(options, mp3binder.Cover(mimeType, bytes.NewReader(picture)))
+	}
+
+	// fall back to the cover embedded in the input files
+	if a.coverFile == "" && !a.noDiscovery {
+		options = append(options, mp3binder.CoverFromInputs(
+			func(index int) bool { return !a.isSpacer(index) },
+			func(_ string, r io.Reader) ([]byte, string, error) {
+				return processEmbeddedCover(r, a.coverOptions())
+			}, func(index int, err error) {
+				fmt.Fprintf(a.status, "! Warning: skipping the cover embedded in '%s': %v\n", a.mediaFiles[index], err)
+			}))
+	}
+
+	// other pictures (e.g. back cover)
+	for _, p := range a.pictures {
+		f, err := a.fs.Open(p.file)
 		if err != nil {
 			return []any{}, closer, err
 		}
+		pictureFiles = append(pictureFiles, f)
+
+		picture, mimeType, err := cover.Process(f, p.mimeType, a.coverOptions())
+		if err != nil {
+			return []any{}, closer, fmt.Errorf("picture file: '%s': %w", p.file, err)
+		}
+
+		options = append(options, mp3binder.Picture(p.kind.pictureType, p.kind.description, mimeType, bytes.NewReader(picture)))
+	}
 
-		options = append(options, mp3binder.Cover(a.coverFileMimeType, coverFile))
+	if a.extractCover != "" {
+		options = append(options, mp3binder.ExtractCover(func(_ string, picture []byte) error {
+			return afero.WriteFile(a.fs, a.extractCover, picture, 0o644)
+		}, func() {
+			fmt.Fprintf(a.status, "! Warning: there is no cover to extract to '%s'\n", a.extractCover)
+		}))
 	}
 
 	// copy metadata
@@ -190,6 +333,20 @@ func (a *application) bindingOptions() ([]any, func(), error) {
 		options = append(options, mp3binder.CopyMetadataFrom(a.copyTagsFromIndex-1, ErrNoTagsInTemplate))
 	}
 
+	// merge metadata
+	if len(a.mergeStrategies) > 0 {
+		options = append(options, mp3binder.MergeMetadata(func(id string) (mp3binder.MergeStrategy, bool) {
+			if strategy, ok := a.mergeStrategies[id]; ok {
+				return strategy, true
+			}
+
+			strategy, ok := a.mergeStrategies[mergeAllTags]
+			return strategy, ok
+		}, mergeSeparator, func(index int) bool {
+			return !a.isSpacer(index)
+		}))
+	}
+
 	// apply metadata
 	options = append(options, mp3binder.ApplyTextMetadata(func(previous map[string]string) map[string]string {
 		// If the title is not explicitly set (empty erases) or copied from a file from the index,
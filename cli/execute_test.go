@@ -121,3 +121,23 @@ func TestInterlaceWithThree(t *testing.T) {
 		assert.Equal(t, len(a.mediaFiles), len(tc.input))
 	}
 }
+
+func TestInterlaceFileIsRewound(t *testing.T) {
+	t.Parallel()
+	tc := &testCollector{}
+	root, fs := newTestFilesystem()
+	mediaFiles := withThreeValidFiles(fs, root)
+	_ = makeEmptyFiles(fs, root, validInterlaceFile1)
+
+	a := newDefaultApplication(aferox.NewAferox(root, fs))
+	a.binder = tc
+	a.mediaFiles = mediaFiles
+	a.interlaceFile = filepath.Join(root, validInterlaceFile1)
+	a.outputPath = filepath.Join(root, validOutputFile)
+
+	err := a.run(nil, nil)
+	if assert.NoError(t, err) && assert.Len(t, tc.input, 5) {
+		// the interlace file is read to its end and rewinds for the next use
+		assert.Same(t, tc.input[1], tc.input[3])
+	}
+}
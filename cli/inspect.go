@@ -0,0 +1,231 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/crra/mp3binder/mp3binder"
+	"github.com/spf13/cobra"
+)
+
+// inspection is the stream and tag information of a mp3 file, printed as text or JSON.
+type inspection struct {
+	File            string             `json:"file"`
+	Format          string             `json:"format"`
+	SamplingRate    int                `json:"samplingRate"`
+	Channels        string             `json:"channels"`
+	VariableBitRate bool               `json:"variableBitRate"`
+	BitRate         int                `json:"bitRate"`
+	Frames          int                `json:"frames"`
+	Duration        float64            `json:"duration"`
+	Header          *inspectedHeader   `json:"header,omitempty"`
+	Tags            map[string]string  `json:"tags"`
+	Pictures        []inspectedPicture `json:"pictures"`
+	Chapters        []inspectedChapter `json:"chapters"`
+}
+
+type inspectedHeader struct {
+	Kind           string `json:"kind"`
+	Frames         uint32 `json:"frames,omitempty"`
+	Bytes          uint32 `json:"bytes,omitempty"`
+	Quality        int    `json:"quality,omitempty"`
+	Encoder        string `json:"encoder,omitempty"`
+	EncoderDelay   int    `json:"encoderDelay,omitempty"`
+	EncoderPadding int    `json:"encoderPadding,omitempty"`
+}
+
+type inspectedPicture struct {
+	Kind        string `json:"kind"`
+	Description string `json:"description"`
+	MimeType    string `json:"mimeType"`
+	Size        int    `json:"size"`
+}
+
+type inspectedChapter struct {
+	ID    string  `json:"id"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Title string  `json:"title"`
+}
+
+// inspectArgs checks the arguments of the 'inspect' command.
+func (a *application) inspectArgs(_ *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return ErrNoInput
+	}
+
+	return nil
+}
+
+// inspect prints the stream and tag information of the files.
+func (a *application) inspect(_ *cobra.Command, args []string) error {
+	inspections := make([]inspection, len(args))
+	for i, file := range args {
+		var err error
+		inspections[i], err = a.inspectFile(file)
+		if err != nil {
+			return fmt.Errorf("'%s': %w", file, err)
+		}
+	}
+
+	if a.inspectJSON {
+		e := json.NewEncoder(a.status)
+		e.SetIndent("", "  ")
+
+		return e.Encode(inspections)
+	}
+
+	for i, in := range inspections {
+		if i > 0 {
+			fmt.Fprintln(a.status)
+		}
+
+		printInspection(a.status, in)
+	}
+
+	return nil
+}
+
+// inspectFile reads the stream and tag information of a file.
+func (a *application) inspectFile(file string) (inspection, error) {
+	f, err := a.fs.Open(file)
+	if err != nil {
+		return inspection{}, err
+	}
+	defer f.Close()
+
+	i, err := mp3binder.Inspect(f)
+	if err != nil {
+		return inspection{}, err
+	}
+
+	in := inspection{
+		File:            file,
+		Format:          i.Stream.Format(),
+		SamplingRate:    i.Stream.SamplingRate,
+		Channels:        i.Stream.Channels(),
+		VariableBitRate: i.Stream.BitRate == 0,
+		BitRate:         i.AverageBitRate,
+		Frames:          i.Frames,
+		Duration:        i.Stream.Duration.Seconds(),
+		Tags:            i.Tags,
+		Pictures:        []inspectedPicture{},
+		Chapters:        []inspectedChapter{},
+	}
+
+	if !in.VariableBitRate {
+		in.BitRate = i.Stream.BitRate
+	}
+
+	if h := i.Header; h != nil {
+		in.Header = &inspectedHeader{
+			Kind:           h.Kind,
+			Frames:         h.Frames,
+			Bytes:          h.Bytes,
+			Quality:        h.Quality,
+			Encoder:        h.Encoder,
+			EncoderDelay:   h.EncoderDelay,
+			EncoderPadding: h.EncoderPadding,
+		}
+	}
+
+	for _, p := range i.Pictures {
+		in.Pictures = append(in.Pictures, inspectedPicture{
+			Kind:        pictureKindName(p.PictureType),
+			Description: p.Description,
+			MimeType:    p.MimeType,
+			Size:        p.Size,
+		})
+	}
+
+	for _, c := range i.Chapters {
+		in.Chapters = append(in.Chapters, inspectedChapter{ID: c.ID, Start: c.Start.Seconds(), End: c.End.Seconds(), Title: c.Title})
+	}
+
+	return in, nil
+}
+
+// pictureKindName returns the name of the picture type (e.g. 'back'), or its number if unknown.
+func pictureKindName(pictureType byte) string {
+	for _, k := range pictureKinds {
+		if k.pictureType == pictureType {
+			return k.name
+		}
+	}
+
+	return strconv.Itoa(int(pictureType))
+}
+
+// printInspection prints the stream and tag information of a file as text.
+func printInspection(w io.Writer, in inspection) {
+	bitRate := fmt.Sprintf("%d kbps (constant)", in.BitRate/1000)
+	if in.VariableBitRate {
+		bitRate = fmt.Sprintf("%d kbps (variable, average)", in.BitRate/1000)
+	}
+
+	fmt.Fprintf(w, "File:     %s\n", in.File)
+	fmt.Fprintf(w, "Format:   %s, %d Hz, %s\n", in.Format, in.SamplingRate, in.Channels)
+	fmt.Fprintf(w, "Bitrate:  %s\n", bitRate)
+	fmt.Fprintf(w, "Duration: %s (%d frames)\n", preciseClock(seconds(in.Duration)), in.Frames)
+
+	if h := in.Header; h != nil {
+		fmt.Fprintf(w, "Header:   %s", h.Kind)
+		if h.Frames > 0 {
+			fmt.Fprintf(w, ", %d frames", h.Frames)
+		}
+		if h.Bytes > 0 {
+			fmt.Fprintf(w, ", %d bytes", h.Bytes)
+		}
+		if h.Quality > 0 {
+			fmt.Fprintf(w, ", quality %d", h.Quality)
+		}
+		if h.Encoder != "" {
+			fmt.Fprintf(w, ", %s (delay %d, padding %d samples)", h.Encoder, h.EncoderDelay, h.EncoderPadding)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(in.Tags) > 0 {
+		fmt.Fprintln(w, "Tags:")
+
+		keys := make([]string, 0, len(in.Tags))
+		for k := range in.Tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			fmt.Fprintf(w, "  %s: %s\n", k, in.Tags[k])
+		}
+	}
+
+	if len(in.Pictures) > 0 {
+		fmt.Fprintln(w, "Pictures:")
+		for _, p := range in.Pictures {
+			fmt.Fprintf(w, "  %s: %s, %d bytes, '%s'\n", p.Kind, p.MimeType, p.Size, p.Description)
+		}
+	}
+
+	if len(in.Chapters) > 0 {
+		fmt.Fprintln(w, "Chapters:")
+		for _, c := range in.Chapters {
+			fmt.Fprintf(w, "  %s - %s: %s (%s)\n", preciseClock(seconds(c.Start)), preciseClock(seconds(c.End)), c.Title, c.ID)
+		}
+	}
+}
+
+// seconds converts seconds to a duration.
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// preciseClock formats the duration with milliseconds (e.g. '1:02.345').
+func preciseClock(d time.Duration) string {
+	d = d.Round(time.Millisecond)
+
+	return fmt.Sprintf("%s.%03d", clock(d.Truncate(time.Second)), (d % time.Second).Milliseconds())
+}
@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	fs2 "io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/carolynvs/aferox"
+	"github.com/crra/mp3binder/encoding/tagfile"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// jobFileName is the name of the job file in the folder of a binding.
+const jobFileName = "mp3binder.yaml"
+
+// job describes a complete binding (e.g. the input files with their chapter titles) of a folder.
+// Relative paths are relative to the folder of the job file.
+type job struct {
+	file string
+
+	Output       string     `yaml:"output"`
+	Cover        string     `yaml:"cover"`
+	Interlace    string     `yaml:"interlace"`
+	FolderSpacer string     `yaml:"folder-spacer"`
+	Intro        string     `yaml:"intro"`
+	Outro        string     `yaml:"outro"`
+	Tags         yaml.Node  `yaml:"tags"`
+	Inputs       []jobInput `yaml:"inputs"`
+}
+
+// jobInput is an input file of a job. It can be written as the name of the file only.
+type jobInput struct {
+	File    string `yaml:"file"`
+	Chapter string `yaml:"chapter"`
+	// interlace file after the input file, overrides the interlace file of the job. Empty for none.
+	Interlace *string `yaml:"interlace"`
+}
+
+func (i *jobInput) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		i.File = node.Value
+		return nil
+	}
+
+	type plain jobInput
+	return node.Decode((*plain)(i))
+}
+
+// readJob reads the job file.
+func readJob(fs aferox.Aferox, file string) (*job, error) {
+	f, err := fs.Open(file)
+	if err != nil {
+		if errors.Is(err, fs2.ErrNotExist) {
+			return nil, fmt.Errorf("job file: '%s': %w", file, ErrFileNotFound)
+		}
+
+		return nil, err
+	}
+	defer f.Close()
+
+	j := &job{file: file}
+	if err := yaml.NewDecoder(f).Decode(j); err != nil {
+		return nil, fmt.Errorf("job file '%s': %v: %w", file, err, ErrInvalidJob)
+	}
+
+	if len(j.Inputs) == 0 {
+		return nil, fmt.Errorf("job file '%s': no inputs: %w", file, ErrInvalidJob)
+	}
+
+	for i, input := range j.Inputs {
+		if strings.TrimSpace(input.File) == "" {
+			return nil, fmt.Errorf("job file '%s': input %d has no file: %w", file, i+1, ErrInvalidJob)
+		}
+
+		// each input is a single file with its own settings (e.g. the chapter title)
+		if info, err := fs.Stat(j.path(input.File)); err == nil && info.IsDir() {
+			return nil, fmt.Errorf("job file '%s': input %d '%s' is a folder, list its files instead: %w", file, i+1, input.File, ErrInvalidJob)
+		}
+	}
+
+	return j, nil
+}
+
+// path returns the path relative to the folder of the job file.
+func (j *job) path(path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(filepath.Dir(j.file), path)
+}
+
+// files returns the paths of the input files.
+func (j *job) files() []string {
+	files := make([]string, len(j.Inputs))
+	for i, input := range j.Inputs {
+		files[i] = j.path(input.File)
+	}
+
+	return files
+}
+
+// entries returns the chapter titles and interlace overrides of the input files.
+func (j *job) entries() []entry {
+	entries := make([]entry, len(j.Inputs))
+	for i, input := range j.Inputs {
+		entries[i].chapterTitle = input.Chapter
+
+		if input.Interlace != nil {
+			interlace := j.path(*input.Interlace)
+			entries[i].interlace = &interlace
+		}
+	}
+
+	return entries
+}
+
+// tags returns the tags of the job. Multiple values for a tag are joined.
+func (j *job) tags() (map[string]string, error) {
+	values, err := tagfile.DecodeYAMLNode(&j.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("job file '%s': tags: %w", j.file, err)
+	}
+
+	return joinTagValues(values), nil
+}
+
+// applyJob sets the output, cover and spacer files of the job, except the flags set on the command
+// line. The job takes precedence over the configs.
+func (a *application) applyJob(explicit map[string]bool) {
+	for _, f := range []struct {
+		flag   string
+		value  string
+		target *string
+	}{
+		{flag: flagOutputFile, value: a.job.Output, target: &a.outputPath},
+		{flag: flagCover, value: a.job.Cover, target: &a.coverFile},
+		{flag: flagInterlaceFile, value: a.job.Interlace, target: &a.interlaceFile},
+		{flag: flagFolderSpacer, value: a.job.FolderSpacer, target: &a.folderSpacer},
+		{flag: flagIntro, value: a.job.Intro, target: &a.introFile},
+		{flag: flagOutro, value: a.job.Outro, target: &a.outroFile},
+	} {
+		if f.value != "" && !explicit[f.flag] {
+			*f.target = a.job.path(f.value)
+		}
+	}
+}
+
+// runArgs is the cobra way of performing checks on the arguments of the 'run' command. The
+// job file is read from the folder provided as argument or the working directory.
+func (a *application) runArgs(c *cobra.Command, args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("only the folder of the job file is accepted: %w", ErrInvalidJob)
+	}
+
+	folder := a.fs.Getwd()
+	if len(args) == 1 {
+		folder = a.fs.Abs(args[0])
+	}
+
+	var err error
+	a.job, err = readJob(a.fs, filepath.Join(folder, jobFileName))
+	if err != nil {
+		return err
+	}
+
+	return a.args(c, []string{folder})
+}
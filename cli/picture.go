@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/carolynvs/aferox"
+	"github.com/crra/id3v2/v2"
+	"github.com/crra/mp3binder/slice"
+)
+
+const (
+	pictureFront     = "front"
+	pictureSeparator = "="
+)
+
+// pictureKind is a type of picture (e.g. the back cover) that can be attached to the output file.
+type pictureKind struct {
+	name        string
+	pictureType byte
+	description string
+	// names of the files found by the discovery (without extension)
+	wellKnownNames []string
+}
+
+var pictureKinds = []pictureKind{
+	{name: pictureFront, pictureType: id3v2.PTFrontCover, description: "Front cover", wellKnownNames: coverFileNames},
+	{name: "back", pictureType: id3v2.PTBackCover, description: "Back cover", wellKnownNames: []string{"back"}},
+	{name: "artist", pictureType: id3v2.PTArtistPerformer, description: "Artist", wellKnownNames: []string{"artist"}},
+	{name: "disc", pictureType: id3v2.PTMedia, description: "Media", wellKnownNames: []string{"disc", "cd", "media"}},
+	{name: "leaflet", pictureType: id3v2.PTLeafletPage, description: "Leaflet page", wellKnownNames: []string{"leaflet", "booklet"}},
+	{name: "lead", pictureType: id3v2.PTLeadArtistSoloist, description: "Lead artist"},
+	{name: "conductor", pictureType: id3v2.PTConductor, description: "Conductor"},
+	{name: "band", pictureType: id3v2.PTBandOrchestra, description: "Band"},
+	{name: "composer", pictureType: id3v2.PTComposer, description: "Composer"},
+	{name: "lyricist", pictureType: id3v2.PTLyricistTextWriter, description: "Lyricist"},
+	{name: "location", pictureType: id3v2.PTRecordingLocation, description: "Recording location"},
+	{name: "illustration", pictureType: id3v2.PTIllustration, description: "Illustration"},
+	{name: "logo", pictureType: id3v2.PTBandArtistLogotype, description: "Band logo"},
+	{name: "publisher", pictureType: id3v2.PTPublisherStudioLogotype, description: "Publisher logo"},
+	{name: "other", pictureType: id3v2.PTOther, description: "Other"},
+}
+
+// picture is an image file that is attached to the output file.
+type picture struct {
+	kind     pictureKind
+	file     string
+	mimeType string
+}
+
+func pictureKindNames() []string {
+	return slice.Map(pictureKinds, func(k pictureKind) string { return k.name })
+}
+
+func pictureKindOf(name string) (pictureKind, bool) {
+	for _, k := range pictureKinds {
+		if k.name == strings.ToLower(strings.TrimSpace(name)) {
+			return k, true
+		}
+	}
+
+	return pictureKind{}, false
+}
+
+// parsePictures parses the pictures in the format 'type=file' (e.g. 'back=back.jpg').
+// Each type can only be set once.
+func parsePictures(args []string) (map[string]string, error) {
+	files := make(map[string]string, len(args))
+
+	for _, arg := range args {
+		name, file, found := strings.Cut(arg, pictureSeparator)
+		if !found || strings.TrimSpace(file) == "" {
+			return nil, fmt.Errorf("picture '%s': must be in the format 'type=file': %w", arg, ErrInvalidPicture)
+		}
+
+		kind, ok := pictureKindOf(name)
+		if !ok {
+			return nil, fmt.Errorf("picture '%s': unknown type '%s', supported: %s: %w", arg, name, strings.Join(pictureKindNames(), ", "), ErrInvalidPicture)
+		}
+
+		if _, exists := files[kind.name]; exists {
+			return nil, fmt.Errorf("picture '%s': the type '%s' is set multiple times: %w", arg, kind.name, ErrInvalidPicture)
+		}
+
+		files[kind.name] = strings.TrimSpace(file)
+	}
+
+	return files, nil
+}
+
+// getPictures returns the explicitly set or discovered pictures for all types except the front cover.
+func getPictures(fs aferox.Aferox, d discovery, files map[string]string) ([]picture, error) {
+	var pictures []picture
+
+	for _, kind := range pictureKinds {
+		if kind.name == pictureFront {
+			continue
+		}
+
+		file, ok := files[kind.name]
+		if !ok && len(kind.wellKnownNames)+len(d.names.Pictures[kind.name]) == 0 {
+			continue
+		}
+
+		file, mimeType, err := lookupMimeType(fs)(d.file(file, kind.name, d.names.isAcceptedCoverFile, d.names.pictureFiles(kind)))
+		if err != nil {
+			return nil, err
+		}
+
+		if file != "" {
+			pictures = append(pictures, picture{kind: kind, file: file, mimeType: mimeType})
+		}
+	}
+
+	return pictures, nil
+}
@@ -0,0 +1,303 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/crra/mp3binder/mp3binder"
+	"github.com/crra/mp3binder/slice"
+)
+
+const (
+	// handling of interlace files with a different stream format than the input files
+	mismatchWarn    = "warn"
+	mismatchSilence = "silence"
+	mismatchError   = "error"
+
+	defaultInterlaceMismatch = mismatchWarn
+)
+
+var interlaceMismatches = []string{mismatchWarn, mismatchSilence, mismatchError}
+
+// entry holds the settings of a media file (e.g. from a job file).
+type entry struct {
+	// the media file is a spacer (e.g. an interlace file or the intro)
+	spacer       bool
+	chapterTitle string
+	// interlace file after the media file, overrides the interlace file if set
+	interlace *string
+	// silence after the media file (e.g. replacing the interlace file), overrides the gap if set
+	gap time.Duration
+	// the part of the media file to bind
+	cut timeRange
+}
+
+// isSpacer returns true if the media file at the index is a spacer (e.g. an interlace file or the intro)
+// rather than an input file.
+func (a *application) isSpacer(index int) bool {
+	return index < len(a.entries) && a.entries[index].spacer
+}
+
+// hasSpacers returns true if any spacer is set (e.g. an interlace file for all or single media files).
+func (a *application) hasSpacers() bool {
+	if a.interlaceFile != "" || a.folderSpacer != "" || a.introFile != "" || a.outroFile != "" {
+		return true
+	}
+
+	for _, e := range a.entries {
+		if e.interlace != nil && *e.interlace != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// spacerAfter returns the interlace file between the media file at the index and the next one:
+// the interlace file of the entry (e.g. from a job file, an empty one omits it), the folder
+// interlace file if the next media file is from another folder or the interlace file.
+func (a *application) spacerAfter(index int) string {
+	if interlace := a.entries[index].interlace; interlace != nil {
+		return *interlace
+	}
+
+	if a.folderSpacer != "" && filepath.Dir(a.mediaFiles[index]) != filepath.Dir(a.mediaFiles[index+1]) {
+		return a.folderSpacer
+	}
+
+	return a.interlaceFile
+}
+
+// insertSpacers puts the intro before the first, the outro after the last and the interlace
+// files between the media files.
+func (a *application) insertSpacers() {
+	mediaFiles := make([]string, 0, len(a.mediaFiles)*2+2)
+	entries := make([]entry, 0, len(a.mediaFiles)*2+2)
+	copyTagsFromIndex := a.copyTagsFromIndex
+
+	addSpacer := func(file string) {
+		if file != "" {
+			mediaFiles = append(mediaFiles, file)
+			entries = append(entries, entry{spacer: true})
+		}
+	}
+
+	addSpacer(a.introFile)
+
+	for i, file := range a.mediaFiles {
+		if i+1 == a.copyTagsFromIndex {
+			copyTagsFromIndex = len(mediaFiles) + 1
+		}
+
+		mediaFiles = append(mediaFiles, file)
+		entries = append(entries, a.entries[i])
+
+		if i < len(a.mediaFiles)-1 {
+			addSpacer(a.spacerAfter(i))
+		}
+	}
+
+	addSpacer(a.outroFile)
+
+	a.mediaFiles = mediaFiles
+	a.entries = entries
+	a.copyTagsFromIndex = copyTagsFromIndex
+}
+
+// parseInterlaceMismatch returns the handling of interlace files that don't match the input files.
+func parseInterlaceMismatch(mismatch string) (string, error) {
+	mismatch = strings.ToLower(strings.TrimSpace(mismatch))
+	if mismatch == "" {
+		return defaultInterlaceMismatch, nil
+	}
+
+	if !slice.Contains(interlaceMismatches, mismatch) {
+		return "", fmt.Errorf("%s: unknown value '%s', supported: %s: %w", flagMismatch, mismatch, strings.Join(interlaceMismatches, ", "), ErrInvalidMismatch)
+	}
+
+	return mismatch, nil
+}
+
+// gapAfter returns the duration of the silence between the media file at the index and the next one.
+func (a *application) gapAfter(index int) time.Duration {
+	if gap := a.entries[index].gap; gap > 0 {
+		return gap
+	}
+
+	if a.isSpacer(index) || a.isSpacer(index+1) {
+		return 0
+	}
+
+	return a.gap
+}
+
+// hasCuts returns true if only a part of any media file is bound.
+func (a *application) hasCuts() bool {
+	for _, e := range a.entries {
+		if e.cut.isSet() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasGaps returns true if silence is put between any media files.
+func (a *application) hasGaps() bool {
+	if a.gap > 0 {
+		return true
+	}
+
+	for _, e := range a.entries {
+		if e.gap > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// streamInfo reads the stream format of a media file, files without frames (e.g. empty files) have none.
+func (a *application) streamInfo(file string) (mp3binder.StreamInfo, bool, error) {
+	f, err := a.fs.Open(file)
+	if err != nil {
+		return mp3binder.StreamInfo{}, false, err
+	}
+	defer f.Close()
+
+	info, ok := mp3binder.ReadStreamInfo(f)
+
+	return info, ok, nil
+}
+
+// checkInterlaceFiles compares the stream format (e.g. the sampling rate) of the spacer files
+// (e.g. the interlace files or the intro) with the input files. Depending on the option, a spacer
+// file that doesn't match is accepted with a warning, replaced with generated silence of the same
+// duration or rejected. Only the spacer files between the input files can be replaced, the intro
+// and the outro are kept with a warning.
+func (a *application) checkInterlaceFiles() error {
+	var inputs []mp3binder.StreamInfo
+	var inputFiles []string
+
+	// check returns the duration of the spacer file if it is replaced with silence
+	check := func(kind, file string) (time.Duration, error) {
+		interlace, ok, err := a.streamInfo(file)
+		if err != nil || !ok {
+			return 0, err
+		}
+
+		if inputs == nil {
+			inputs = []mp3binder.StreamInfo{}
+			for _, mediaFile := range a.mediaFiles {
+				input, ok, err := a.streamInfo(mediaFile)
+				if err != nil {
+					return 0, err
+				}
+
+				if ok {
+					inputs = append(inputs, input)
+					inputFiles = append(inputFiles, mediaFile)
+				}
+			}
+		}
+
+		for i, input := range inputs {
+			if interlace.Matches(input) {
+				continue
+			}
+
+			switch a.interlaceMismatch {
+			case mismatchSilence:
+				return interlace.Duration, nil
+			case mismatchError:
+				return 0, fmt.Errorf("%s '%s' (%s) and input file '%s' (%s): %w", kind, file, interlace, inputFiles[i], input, ErrInterlaceMismatch)
+			default:
+				fmt.Fprintf(a.status, "! Warning: the %s '%s' (%s) does not match the input file '%s' (%s), use '--%s %s' to replace it with silence of the same duration\n",
+					kind, file, interlace, inputFiles[i], input, flagMismatch, mismatchSilence)
+				return 0, nil
+			}
+		}
+
+		return 0, nil
+	}
+
+	// replace puts the silence after each media file followed by the spacer file, the silence
+	// takes the place of the spacer file and not of the gap
+	replace := func(file string, silence time.Duration) {
+		a.statusPrinter.interlaceReplaced(file, silence)
+
+		if len(a.entries) != len(a.mediaFiles) {
+			a.entries = make([]entry, len(a.mediaFiles))
+		}
+
+		for i := 0; i < len(a.mediaFiles)-1; i++ {
+			if a.spacerAfter(i) == file {
+				none := ""
+				a.entries[i].interlace = &none
+				a.entries[i].gap = silence
+			}
+		}
+	}
+
+	for _, s := range []struct {
+		kind string
+		file *string
+	}{
+		{kind: "interlace file", file: &a.interlaceFile},
+		{kind: "folder spacer", file: &a.folderSpacer},
+	} {
+		if *s.file == "" {
+			continue
+		}
+
+		silence, err := check(s.kind, *s.file)
+		if err != nil {
+			return err
+		}
+
+		if silence > 0 {
+			replace(*s.file, silence)
+			*s.file = ""
+		}
+	}
+
+	// interlace files of single entries (e.g. from a job file)
+	for i := range a.entries {
+		if interlace := a.entries[i].interlace; interlace != nil && *interlace != "" {
+			silence, err := check("interlace file", *interlace)
+			if err != nil {
+				return err
+			}
+
+			if silence > 0 {
+				replace(*interlace, silence)
+			}
+		}
+	}
+
+	// there is no silence before the first or after the last input file
+	for _, s := range []struct {
+		kind string
+		file string
+	}{
+		{kind: "intro", file: a.introFile},
+		{kind: "outro", file: a.outroFile},
+	} {
+		if s.file == "" {
+			continue
+		}
+
+		silence, err := check(s.kind, s.file)
+		if err != nil {
+			return err
+		}
+
+		if silence > 0 {
+			fmt.Fprintf(a.status, "! Warning: the %s '%s' does not match the input files and is kept, only the spacers between the input files are replaced with silence\n", s.kind, s.file)
+		}
+	}
+
+	return nil
+}
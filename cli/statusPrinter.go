@@ -5,7 +5,12 @@ import (
 	"fmt"
 	"io"
 	"path/filepath"
+	"sort"
 	"strconv"
+	"time"
+
+	"github.com/crra/mp3binder/mp3binder"
+	"github.com/crra/mp3binder/mp3binder/tags"
 )
 
 type discardingPrinter struct {
@@ -15,9 +20,18 @@ type discardingPrinter struct {
 func (d *discardingPrinter) language(language string)                                    {}
 func (d *discardingPrinter) listInputFiles(mediaFiles []string, outputFile string)       {}
 func (d *discardingPrinter) listMediaFilesAfterInterlace(mediaFiles []string)            {}
+func (d *discardingPrinter) configFile(file string)                                      {}
+func (d *discardingPrinter) jobFile(file string)                                         {}
+func (d *discardingPrinter) discoveredFile(fileType, file, dir string)                   {}
 func (d *discardingPrinter) coverFile(file string)                                       {}
+func (d *discardingPrinter) pictureFile(description, file string)                        {}
+func (d *discardingPrinter) spacerFile(kind, file string)                                {}
 func (d *discardingPrinter) interlaceFile(file string)                                   {}
+func (d *discardingPrinter) cutFile(file string, cut timeRange)                          {}
+func (d *discardingPrinter) gap(gap time.Duration)                                       {}
+func (d *discardingPrinter) interlaceReplaced(file string, silence time.Duration)        {}
 func (d *discardingPrinter) copyTagsFrom(file string)                                    {}
+func (d *discardingPrinter) mergeTags(strategies map[string]mp3binder.MergeStrategy)     {}
 func (d *discardingPrinter) tagsToApply(tags map[string]string, tagResolver tagResolver) {}
 
 func (d *discardingPrinter) actionObserver(stage, action string) {}
@@ -25,6 +39,10 @@ func (d *discardingPrinter) newBindObserver(mediaFiles []string) func(index int)
 	return func(index int) {}
 }
 
+func (d *discardingPrinter) newNormalizeObserver(mediaFiles []string) func(index int, adjustment float64) {
+	return func(index int, adjustment float64) {}
+}
+
 func (d *discardingPrinter) newTagCopyObserver(copyFilename string) func(tag, value string, err error) {
 	return func(tag, value string, err error) {}
 }
@@ -66,28 +84,74 @@ func (p *verbosePrinter) list(mediaFiles []string, title string) {
 	}
 }
 
+func (p *verbosePrinter) configFile(file string) {
+	fmt.Fprintf(p.output, "The following config file will be used: '%s'\n", file)
+}
+
+func (p *verbosePrinter) jobFile(file string) {
+	fmt.Fprintf(p.output, "The following job file will be used: '%s'\n", file)
+}
+
+func (p *verbosePrinter) discoveredFile(fileType, file, dir string) {
+	fmt.Fprintf(p.output, "The %s file '%s' was discovered in: '%s'\n", fileType, filepath.Base(file), dir)
+}
+
 func (p *verbosePrinter) coverFile(coverFile string) {
 	if coverFile != "" {
 		fmt.Fprintf(p.output, "The following file will be used as cover: '%s'\n", coverFile)
 	}
 }
 
+func (p *verbosePrinter) pictureFile(description, file string) {
+	fmt.Fprintf(p.output, "The following file will be used as picture '%s': '%s'\n", description, file)
+}
+
 func (p *verbosePrinter) interlaceFile(interlaceFile string) {
 	if interlaceFile != "" {
 		fmt.Fprintf(p.output, "The following file will be used as interlace: '%s'\n", interlaceFile)
 	}
 }
 
+func (p *verbosePrinter) cutFile(file string, cut timeRange) {
+	fmt.Fprintf(p.output, "The file '%s' will be cut to: '%s'\n", file, cut)
+}
+
+func (p *verbosePrinter) gap(gap time.Duration) {
+	fmt.Fprintf(p.output, "Silence of '%s' will be put between the input files\n", gap)
+}
+
+func (p *verbosePrinter) interlaceReplaced(file string, silence time.Duration) {
+	fmt.Fprintf(p.output, "The interlace file '%s' does not match the input files and will be replaced with silence of '%s'\n", file, silence)
+}
+
+func (p *verbosePrinter) spacerFile(kind, file string) {
+	fmt.Fprintf(p.output, "The following file will be used as %s: '%s'\n", kind, file)
+}
+
 func (p *verbosePrinter) copyTagsFrom(mediaFile string) {
 	fmt.Fprintf(p.output, "Id3v2 tags will be copied from file: '%s'\n", mediaFile)
 }
 
-func (p *verbosePrinter) tagsToApply(tags map[string]string, tagResolver tagResolver) {
+func (p *verbosePrinter) mergeTags(strategies map[string]mp3binder.MergeStrategy) {
+	fmt.Fprintln(p.output, "Id3v2 tags will be merged from all input files:")
+
+	ids := make([]string, 0, len(strategies))
+	for id := range strategies {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		fmt.Fprintf(p.output, "- %s: %s\n", id, strategies[id])
+	}
+}
+
+func (p *verbosePrinter) tagsToApply(values map[string]string, tagResolver tagResolver) {
 	fmt.Fprintln(p.output, "The following id3v2 tags will be applied:")
 
-	for k := range tags {
-		v := tags[k]
-		description, err := tagResolver.DescriptionFor(k)
+	for k := range values {
+		v := values[k]
+		description, err := tagResolver.DescriptionFor(tags.ParseKey(k).ID)
 		switch {
 		case v == "" && err != nil:
 			fmt.Fprintf(p.output, "- Not well-known '%s': will be removed if present in the output\n", k)
@@ -111,6 +175,12 @@ func (p *verbosePrinter) newBindObserver(mediaFiles []string) func(index int) {
 	}
 }
 
+func (p *verbosePrinter) newNormalizeObserver(mediaFiles []string) func(index int, adjustment float64) {
+	return func(index int, adjustment float64) {
+		fmt.Fprintf(p.output, "- Normalizing '%s': %+.1f dB\n", filepath.Base(mediaFiles[index]), adjustment)
+	}
+}
+
 func (p *verbosePrinter) newTagCopyObserver(copyFilename string) func(tag, value string, err error) {
 	return func(tag, value string, err error) {
 		switch {
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	fs2 "io/fs"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/carolynvs/aferox"
+)
+
+const (
+	// separates the media file and the time range (e.g. 'intro.mp3@0:05-')
+	timeRangeSeparator = "@"
+	// separates the start and the end of a time range (e.g. '1:00-2:30')
+	timeRangeDelimiter = "-"
+	// separates the hours, minutes and seconds of a time (e.g. '1:02:03.5')
+	timeDelimiter = ":"
+)
+
+// timeRangeSyntax matches the characters of a time range (e.g. '0:05-' or '1:00-1:02:03.5'), the
+// times are checked when parsed
+var timeRangeSyntax = regexp.MustCompile(`^[\d:.]*-[\d:.]*$`)
+
+// timeRange is the part of a media file to bind. A zero end is the end of the file.
+type timeRange struct {
+	start time.Duration
+	end   time.Duration
+}
+
+func (r timeRange) isSet() bool {
+	return r.start > 0 || r.end > 0
+}
+
+// String implements the fmt.Stringer interface (e.g. '0:05-' or '1:00-1:02:03.5').
+func (r timeRange) String() string {
+	return rangeClock(r.start) + timeRangeDelimiter + rangeClock(r.end)
+}
+
+// rangeClock formats a time of a time range like the input (e.g. '45:00' or '0:02.5'), zero is omitted.
+func rangeClock(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+
+	s := clock(d.Truncate(time.Second))
+	if ms := (d % time.Second).Milliseconds(); ms > 0 {
+		s += strings.TrimRight(fmt.Sprintf(".%03d", ms), "0")
+	}
+
+	return s
+}
+
+// splitTimeRange separates the time range from a media file (e.g. 'intro.mp3@0:05-' or 'ch1.mp3@-45:00').
+// An existing file or a suffix that is not a time range (e.g. 'a@b.mp3' or 'a@b/x-y.mp3') is a media
+// file without a time range.
+func splitTimeRange(fs aferox.Aferox, arg string) (string, timeRange, error) {
+	i := strings.LastIndex(arg, timeRangeSeparator)
+	if i < 0 || !timeRangeSyntax.MatchString(arg[i+len(timeRangeSeparator):]) {
+		return arg, timeRange{}, nil
+	}
+
+	if _, err := fs.Stat(arg); err == nil || !errors.Is(err, fs2.ErrNotExist) {
+		return arg, timeRange{}, nil
+	}
+
+	r, err := parseTimeRange(arg[i+len(timeRangeSeparator):])
+	if err != nil {
+		return "", timeRange{}, fmt.Errorf("file: '%s': %w", arg, err)
+	}
+
+	return arg[:i], r, nil
+}
+
+// parseTimeRange parses a time range with an optional start and end (e.g. '0:05-', '-45:00' or '1:00-2:30').
+func parseTimeRange(s string) (timeRange, error) {
+	start, end, ok := strings.Cut(s, timeRangeDelimiter)
+	if !ok || strings.Contains(end, timeRangeDelimiter) {
+		return timeRange{}, fmt.Errorf("time range '%s' takes the format 'start-end': %w", s, ErrInvalidRange)
+	}
+
+	var r timeRange
+	var err error
+
+	if r.start, err = parseTime(start); err != nil {
+		return timeRange{}, fmt.Errorf("time range '%s': %w", s, err)
+	}
+
+	if r.end, err = parseTime(end); err != nil {
+		return timeRange{}, fmt.Errorf("time range '%s': %w", s, err)
+	}
+
+	if r.end > 0 && r.end <= r.start {
+		return timeRange{}, fmt.Errorf("time range '%s' ends before it starts: %w", s, ErrInvalidRange)
+	}
+
+	return r, nil
+}
+
+// parseTime parses a time in seconds, minutes and seconds or hours, minutes and seconds
+// (e.g. '5', '45:00' or '1:02:03.5'). An empty time is zero.
+func parseTime(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+
+	parts := strings.Split(s, timeDelimiter)
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("time '%s' takes the format '[[hours:]minutes:]seconds': %w", s, ErrInvalidRange)
+	}
+
+	var d time.Duration
+	for i, part := range parts {
+		last := i == len(parts)-1
+
+		var value float64
+		var err error
+		if last {
+			value, err = strconv.ParseFloat(part, 64)
+		} else {
+			var n int
+			n, err = strconv.Atoi(part)
+			value = float64(n)
+		}
+
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) || value < 0 || (i > 0 && value >= 60) {
+			return 0, fmt.Errorf("time '%s' takes the format '[[hours:]minutes:]seconds': %w", s, ErrInvalidRange)
+		}
+
+		d = d*60 + time.Duration(value*float64(time.Second))
+	}
+
+	return d, nil
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/crra/mp3binder/mp3binder"
+	"github.com/spf13/cobra"
+)
+
+// verifyArgs checks the arguments of the 'verify' command: the output file and its input files.
+func (a *application) verifyArgs(_ *cobra.Command, args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("the output file and its input files are required: %w", ErrNoInput)
+	}
+
+	return nil
+}
+
+// verify checks that the output file (the first argument) is bound from the input files and
+// prints the differences.
+func (a *application) verify(_ *cobra.Command, args []string) error {
+	outputFile, inputFiles := args[0], args[1:]
+
+	output, err := a.fs.Open(outputFile)
+	if err != nil {
+		return err
+	}
+	defer output.Close()
+
+	// an input file can be listed multiple times (e.g. an interlace file)
+	inputs, closer, err := openFilesOnce(a.fs, inputFiles)
+	defer closer()
+	if err != nil {
+		return err
+	}
+
+	differences, err := mp3binder.Verify(output, inputs)
+	if err != nil {
+		return fmt.Errorf("'%s': %w", outputFile, err)
+	}
+
+	if len(differences) == 0 {
+		fmt.Fprintf(a.status, "'%s' matches its %d input files\n", outputFile, len(inputFiles))
+		return nil
+	}
+
+	fmt.Fprintf(a.status, "'%s' does not match its input files:\n", outputFile)
+	for _, d := range differences {
+		if d.Input < 0 {
+			fmt.Fprintf(a.status, "- %s\n", d.Message)
+		} else {
+			fmt.Fprintf(a.status, "- '%s' (input %d): %s\n", inputFiles[d.Input], d.Input+1, d.Message)
+		}
+	}
+
+	return fmt.Errorf("'%s': %d difference(s): %w", outputFile, len(differences), ErrVerificationFailed)
+}
@@ -47,7 +47,7 @@ func main() {
 
 	fs := afero.NewOsFs()
 
-	resolver := tags.NewV24(cli.ErrTagNonStandard)
+	resolver := tags.NewV24(cli.ErrTagNonStandard, cli.ErrTagInvalid, cli.ErrTagInvalidValue)
 	binder := mp3binder.New(resolver)
 
 	userLocale, err := jibber_jabber.DetectIETF()
@@ -55,8 +55,14 @@ func main() {
 		userLocale = defaultLocale
 	}
 
+	// the user config is optional
+	var userConfigFile string
+	if userConfigDir, err := os.UserConfigDir(); err == nil {
+		userConfigFile = cli.UserConfigFile(userConfigDir)
+	}
+
 	// run the program and clean up
-	if err := cli.New(context, url, name, version, os.Stdout, fs, cwd, binder, resolver, userLocale).Execute(); err != nil {
+	if err := cli.New(context, url, name, version, os.Stdout, fs, cwd, binder, resolver, userLocale, userConfigFile).Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
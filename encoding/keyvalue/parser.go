@@ -8,10 +8,12 @@ import (
 const (
 	keyValueSeparator = ":="
 	pairSeparator     = ", "
+	qualifiersOpen    = '['
+	qualifiersClose   = ']'
 )
 
 // StringAsStringMap takes a string (e.g "key=value,key2=value") and puts the keys
-// and values in a map of strings.
+// and values in a map of strings. Separators in brackets of a key are kept (e.g. "COMM[eng:description]=value").
 func StringAsStringMap(input string) (map[string]string, error) {
 	kv := make(map[string]string)
 
@@ -23,6 +25,8 @@ func StringAsStringMap(input string) (map[string]string, error) {
 
 	// rune of the last quote. Allows nesting "'" and '"'
 	lastQuote := rune(0)
+	// brackets in the key group qualifiers
+	insideOfBrackets := false
 	finalIndexOfInput := len(input) - 1
 
 	for i, r := range input {
@@ -36,8 +40,18 @@ func StringAsStringMap(input string) (map[string]string, error) {
 		case outsideOfQuote && unicode.In(r, unicode.Quotation_Mark):
 			// opening quote, swallow
 			lastQuote = r
-		case outsideOfQuote && strings.ContainsRune(keyValueSeparator, r):
+		case outsideOfQuote && b == &keyB && r == qualifiersOpen:
+			insideOfBrackets = true
+			b.WriteRune(r)
+		case outsideOfQuote && insideOfBrackets && r == qualifiersClose:
+			insideOfBrackets = false
+			b.WriteRune(r)
+		case insideOfBrackets:
+			// collect separators and whitespace in brackets
+			b.WriteRune(r)
+		case outsideOfQuote && b == &keyB && strings.ContainsRune(keyValueSeparator, r):
 			// start value, swallow
+			// separators in the value are kept (e.g. urls)
 			// switch buffer
 			b = &valueB
 		case outsideOfQuote && unicode.In(r, unicode.White_Space):
@@ -59,6 +73,7 @@ func StringAsStringMap(input string) (map[string]string, error) {
 			keyB.Reset()
 			valueB.Reset()
 			b = &keyB
+			insideOfBrackets = false
 		}
 	}
 
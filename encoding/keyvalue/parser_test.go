@@ -58,6 +58,12 @@ func TestParse(t *testing.T) {
 		// equal in key/value
 		{title: "equal in key", input: `"key=1"="value",key2=value2`, expected: map[string]string{"key=1": "value", "key2": "value2"}},
 		{title: "equal in value", input: `key="value=1",key2=value2`, expected: map[string]string{"key": "value=1", "key2": "value2"}},
+
+		// brackets in key
+		{title: "colon in brackets", input: "COMM[eng:desc]=value", expected: map[string]string{"COMM[eng:desc]": "value"}},
+		{title: "separators in brackets", input: "TXXX[a=b, c]=value,key2=value2", expected: map[string]string{"TXXX[a=b, c]": "value", "key2": "value2"}},
+		{title: "url in value", input: "WXXX[Shop]=https://example.com", expected: map[string]string{"WXXX[Shop]": "https://example.com"}},
+		{title: "brackets with colon separator", input: "TXXX[ASIN]:B000", expected: map[string]string{"TXXX[ASIN]": "B000"}},
 	} {
 		f := f // pin
 		t.Run(f.title, func(t *testing.T) {
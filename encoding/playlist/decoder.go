@@ -0,0 +1,194 @@
+// Package playlist reads the entries of input lists and playlists in the M3U, M3U8 or PLS format.
+package playlist
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+var ErrInvalidFormat = errors.New("invalid playlist")
+
+const (
+	extendedM3UHeader = "#EXTM3U"
+	extendedM3UInfo   = "#EXTINF:"
+	plsSection        = "[playlist]"
+	plsFile           = "file"
+	plsTitle          = "title"
+	keyValueSeparator = "="
+	commentPrefix     = "#"
+)
+
+// Entry is a path (or pattern) of a playlist with an optional title.
+type Entry struct {
+	Path  string
+	Title string
+}
+
+// Decode reads the entries of a playlist. The format is chosen by the extension of the file name:
+// '.m3u' or '.m3u8', '.pls', any other extension is read as a path per line. Empty lines and
+// lines starting with '#' are ignored.
+func Decode(name string, r io.Reader) ([]Entry, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".m3u":
+		// classic M3U files are encoded in Windows-1252
+		return decodeM3U(asUTF8(content))
+	case ".m3u8":
+		return decodeM3U(content)
+	case ".pls":
+		return decodePLS(content)
+	default:
+		return decodeList(content)
+	}
+}
+
+// asUTF8 converts content from Windows-1252 if it is not valid UTF-8.
+func asUTF8(content []byte) []byte {
+	if utf8.Valid(content) {
+		return content
+	}
+
+	converted, err := charmap.Windows1252.NewDecoder().Bytes(content)
+	if err != nil {
+		return content
+	}
+
+	return converted
+}
+
+// lines calls the function for each trimmed line, starting with the line number 1. A byte order mark is removed.
+func lines(content []byte, fn func(number int, line string) error) error {
+	s := bufio.NewScanner(bytes.NewReader(bytes.TrimPrefix(content, []byte("\ufeff"))))
+	for number := 1; s.Scan(); number++ {
+		if err := fn(number, strings.TrimSpace(s.Text())); err != nil {
+			return err
+		}
+	}
+
+	return s.Err()
+}
+
+func isComment(line string) bool {
+	return strings.HasPrefix(line, commentPrefix)
+}
+
+func decodeList(content []byte) ([]Entry, error) {
+	entries := []Entry{}
+
+	err := lines(content, func(_ int, line string) error {
+		if line != "" && !isComment(line) {
+			entries = append(entries, Entry{Path: line})
+		}
+
+		return nil
+	})
+
+	return entries, err
+}
+
+// decodeM3U reads a (extended) M3U playlist. The title of '#EXTINF:duration,title' applies to the next path.
+func decodeM3U(content []byte) ([]Entry, error) {
+	entries := []Entry{}
+	var title string
+
+	err := lines(content, func(number int, line string) error {
+		switch {
+		case line == "" || line == extendedM3UHeader:
+		case strings.HasPrefix(line, extendedM3UInfo):
+			// the duration may be followed by attributes (e.g. '#EXTINF:-1 key="value",title')
+			_, t, found := strings.Cut(strings.TrimPrefix(line, extendedM3UInfo), ",")
+			if !found {
+				return fmt.Errorf("line %d: missing title: %w", number, ErrInvalidFormat)
+			}
+			title = strings.TrimSpace(t)
+		case isComment(line):
+		default:
+			entries = append(entries, Entry{Path: line, Title: title})
+			title = ""
+		}
+
+		return nil
+	})
+
+	return entries, err
+}
+
+// decodePLS reads a PLS playlist with the keys 'FileN' and 'TitleN'. The entries are ordered by their number.
+func decodePLS(content []byte) ([]Entry, error) {
+	byNumber := make(map[int]*Entry)
+
+	err := lines(content, func(number int, line string) error {
+		if line == "" || isComment(line) || strings.EqualFold(line, plsSection) {
+			return nil
+		}
+
+		key, value, found := strings.Cut(line, keyValueSeparator)
+		if !found {
+			return fmt.Errorf("line %d: missing '%s': %w", number, keyValueSeparator, ErrInvalidFormat)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		var field string
+		switch {
+		case strings.HasPrefix(key, plsFile):
+			field = plsFile
+		case strings.HasPrefix(key, plsTitle):
+			field = plsTitle
+		default:
+			// e.g. 'NumberOfEntries', 'Version' or 'LengthN'
+			return nil
+		}
+
+		index, err := strconv.Atoi(strings.TrimPrefix(key, field))
+		if err != nil {
+			return fmt.Errorf("line %d: invalid key '%s': %w", number, key, ErrInvalidFormat)
+		}
+
+		if byNumber[index] == nil {
+			byNumber[index] = &Entry{}
+		}
+
+		if field == plsFile {
+			byNumber[index].Path = value
+		} else {
+			byNumber[index].Title = value
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	numbers := make([]int, 0, len(byNumber))
+	for n := range byNumber {
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+
+	entries := make([]Entry, 0, len(numbers))
+	for _, n := range numbers {
+		if byNumber[n].Path == "" {
+			return nil, fmt.Errorf("entry %d: missing file: %w", n, ErrInvalidFormat)
+		}
+
+		entries = append(entries, *byNumber[n])
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,81 @@
+package playlist
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []struct {
+		title    string
+		name     string
+		input    string
+		expected []Entry
+	}{
+		// list
+		{title: "list", name: "files.txt", input: "01.mp3\n02.mp3", expected: []Entry{{Path: "01.mp3"}, {Path: "02.mp3"}}},
+		{title: "list without extension", name: "files", input: "01.mp3", expected: []Entry{{Path: "01.mp3"}}},
+		{
+			title: "list comments and empty lines", name: "files.txt",
+			input:    "# intro\n\n  01.mp3  \n# outro\n*.mp3\r\n",
+			expected: []Entry{{Path: "01.mp3"}, {Path: "*.mp3"}},
+		},
+		{title: "list semicolon is not a comment", name: "files.txt", input: ";01.mp3", expected: []Entry{{Path: ";01.mp3"}}},
+		{title: "list byte order mark", name: "files.txt", input: "\ufeff01.mp3", expected: []Entry{{Path: "01.mp3"}}},
+
+		// m3u
+		{
+			title: "m3u", name: "list.m3u",
+			input:    "#EXTM3U\n#EXTINF:123,First, the intro\n01.mp3\n\n# comment\n02.mp3\n",
+			expected: []Entry{{Path: "01.mp3", Title: "First, the intro"}, {Path: "02.mp3"}},
+		},
+		{title: "m3u8 attributes", name: "list.M3U8", input: "#EXTINF:-1 tvg-name=\"x\",Ünïcode\nsub/01.mp3", expected: []Entry{{Path: "sub/01.mp3", Title: "Ünïcode"}}},
+		{title: "m3u plain", name: "list.m3u", input: "01.mp3\n02.mp3", expected: []Entry{{Path: "01.mp3"}, {Path: "02.mp3"}}},
+		{title: "m3u windows-1252", name: "list.m3u", input: "#EXTINF:1,Caf\xe9\n01.mp3", expected: []Entry{{Path: "01.mp3", Title: "Café"}}},
+
+		// pls
+		{
+			title: "pls", name: "list.pls",
+			input:    "[playlist]\nFile2=02.mp3\nTitle2=Second\nFile1=01.mp3\nLength1=-1\nNumberOfEntries=2\nVersion=2\n",
+			expected: []Entry{{Path: "01.mp3"}, {Path: "02.mp3", Title: "Second"}},
+		},
+	} {
+		f := f // pin
+		t.Run(f.title, func(t *testing.T) {
+			t.Parallel()
+
+			entries, err := Decode(f.name, strings.NewReader(f.input))
+			if assert.NoError(t, err) {
+				assert.Equal(t, f.expected, entries)
+			}
+		})
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []struct {
+		title string
+		name  string
+		input string
+	}{
+		{title: "m3u info without title", name: "list.m3u", input: "#EXTINF:123\n01.mp3"},
+		{title: "pls without separator", name: "list.pls", input: "[playlist]\nFile1"},
+		{title: "pls invalid number", name: "list.pls", input: "[playlist]\nFileOne=01.mp3"},
+		{title: "pls title without file", name: "list.pls", input: "[playlist]\nFile1=01.mp3\nTitle2=Second"},
+	} {
+		f := f // pin
+		t.Run(f.title, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Decode(f.name, bytes.NewReader([]byte(f.input)))
+			assert.ErrorIs(t, err, ErrInvalidFormat)
+		})
+	}
+}
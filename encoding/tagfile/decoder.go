@@ -0,0 +1,188 @@
+// Package tagfile reads tags from sidecar files in the JSON, YAML or key/value format.
+package tagfile
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var ErrInvalidFormat = errors.New("invalid tags file")
+
+const (
+	keyValueSeparator = "="
+	quotes            = `"'`
+)
+
+var commentPrefixes = []string{"#", ";"}
+
+// Decode reads the tags from a sidecar file. The format is chosen by the extension of the
+// file name: '.json', '.yaml' or '.yml', any other extension is read as 'KEY=value' per line.
+// Every key can hold multiple values.
+func Decode(name string, r io.Reader) (map[string][]string, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		return decodeJSON(r)
+	case ".yaml", ".yml":
+		return decodeYAML(r)
+	default:
+		return decodeKeyValue(r)
+	}
+}
+
+func decodeJSON(r io.Reader) (map[string][]string, error) {
+	raw := make(map[string]any)
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("%v: %w", err, ErrInvalidFormat)
+	}
+
+	return asStringSlices(raw)
+}
+
+// decodeYAML reads the YAML nodes rather than the decoded values to keep the scalars
+// as written (e.g. '01' or dates).
+func decodeYAML(r io.Reader) (map[string][]string, error) {
+	var node yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&node); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%v: %w", err, ErrInvalidFormat)
+	}
+
+	return DecodeYAMLNode(&node)
+}
+
+// DecodeYAMLNode reads the tags from a YAML mapping (e.g. a section of a larger document).
+// An empty node has no tags.
+func DecodeYAMLNode(node *yaml.Node) (map[string][]string, error) {
+	raw := make(map[string]yaml.Node)
+	if !node.IsZero() {
+		if err := node.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("%v: %w", err, ErrInvalidFormat)
+		}
+	}
+
+	tags := make(map[string][]string, len(raw))
+	for k, node := range raw {
+		switch node.Kind {
+		case yaml.ScalarNode:
+			tags[k] = append(tags[k], yamlScalar(&node))
+		case yaml.SequenceNode:
+			for _, e := range node.Content {
+				if e.Kind != yaml.ScalarNode {
+					return nil, fmt.Errorf("key '%s' has an unsupported value: %w", k, ErrInvalidFormat)
+				}
+
+				tags[k] = append(tags[k], yamlScalar(e))
+			}
+		default:
+			return nil, fmt.Errorf("key '%s' has an unsupported value: %w", k, ErrInvalidFormat)
+		}
+	}
+
+	return tags, nil
+}
+
+func yamlScalar(node *yaml.Node) string {
+	if node.Tag == "!!null" {
+		return ""
+	}
+
+	return node.Value
+}
+
+// asStringSlices converts the values of a decoded document (scalars or lists of scalars) to strings.
+func asStringSlices(raw map[string]any) (map[string][]string, error) {
+	tags := make(map[string][]string, len(raw))
+
+	for k, value := range raw {
+		switch v := value.(type) {
+		case []any:
+			for _, e := range v {
+				s, err := asString(k, e)
+				if err != nil {
+					return nil, err
+				}
+
+				tags[k] = append(tags[k], s)
+			}
+		default:
+			s, err := asString(k, v)
+			if err != nil {
+				return nil, err
+			}
+
+			tags[k] = append(tags[k], s)
+		}
+	}
+
+	return tags, nil
+}
+
+func asString(key string, v any) (string, error) {
+	switch vv := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return vv, nil
+	case bool, int, int64, uint64, float64:
+		return fmt.Sprint(vv), nil
+	default:
+		return "", fmt.Errorf("key '%s' has an unsupported value: %w", key, ErrInvalidFormat)
+	}
+}
+
+// decodeKeyValue reads a 'KEY=value' pair per line. Empty lines and lines starting
+// with '#' or ';' are ignored. Repeated keys add further values.
+func decodeKeyValue(r io.Reader) (map[string][]string, error) {
+	tags := make(map[string][]string)
+
+	s := bufio.NewScanner(r)
+	for lineNumber := 1; s.Scan(); lineNumber++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || hasAnyPrefix(line, commentPrefixes) {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, keyValueSeparator)
+		if !found {
+			return nil, fmt.Errorf("line %d: missing '%s': %w", lineNumber, keyValueSeparator, ErrInvalidFormat)
+		}
+
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, fmt.Errorf("line %d: missing key: %w", lineNumber, ErrInvalidFormat)
+		}
+
+		tags[key] = append(tags[key], unquote(strings.TrimSpace(value)))
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// unquote removes matching surrounding quotes.
+func unquote(s string) string {
+	if len(s) >= 2 && s[0] == s[len(s)-1] && strings.ContainsRune(quotes, rune(s[0])) {
+		return s[1 : len(s)-1]
+	}
+
+	return s
+}
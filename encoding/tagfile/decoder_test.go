@@ -0,0 +1,109 @@
+package tagfile
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []struct {
+		title    string
+		name     string
+		input    string
+		expected map[string][]string
+	}{
+		// key value
+		{title: "key value", name: "tags.txt", input: "TALB=Album", expected: map[string][]string{"TALB": {"Album"}}},
+		{title: "key value with spaces", name: "tags.txt", input: "  TALB = The Album  ", expected: map[string][]string{"TALB": {"The Album"}}},
+		{title: "key value quoted", name: "tags.txt", input: `TALB=" The Album "`, expected: map[string][]string{"TALB": {" The Album "}}},
+		{title: "key value with equal in value", name: "tags.txt", input: "TIT2=a=b", expected: map[string][]string{"TIT2": {"a=b"}}},
+		{title: "key value multiple values", name: "tags.txt", input: "TPE1=One\nTPE1=Two", expected: map[string][]string{"TPE1": {"One", "Two"}}},
+		{
+			title: "key value comments and empty lines", name: "tags.txt",
+			input:    "# comment\n\n; another comment\nTALB=Album\n",
+			expected: map[string][]string{"TALB": {"Album"}},
+		},
+		{
+			title: "key value qualified keys", name: "tags.txt",
+			input:    "COMM:eng:Notes=Some notes\nTXXX:ASIN=B000",
+			expected: map[string][]string{"COMM:eng:Notes": {"Some notes"}, "TXXX:ASIN": {"B000"}},
+		},
+		{title: "key value empty value", name: "tags.txt", input: "TALB=", expected: map[string][]string{"TALB": {""}}},
+
+		// json
+		{title: "json", name: "tags.json", input: `{"TALB": "Album"}`, expected: map[string][]string{"TALB": {"Album"}}},
+		{title: "json number", name: "tags.JSON", input: `{"TRCK": 3}`, expected: map[string][]string{"TRCK": {"3"}}},
+		{title: "json list", name: "tags.json", input: `{"TPE1": ["One", "Two"]}`, expected: map[string][]string{"TPE1": {"One", "Two"}}},
+		{title: "json null", name: "tags.json", input: `{"TALB": null}`, expected: map[string][]string{"TALB": {""}}},
+
+		// yaml
+		{title: "yaml", name: "tags.yaml", input: "TALB: Album", expected: map[string][]string{"TALB": {"Album"}}},
+		{title: "yml", name: "tags.yml", input: "TALB: Album", expected: map[string][]string{"TALB": {"Album"}}},
+		{title: "yaml number", name: "tags.yaml", input: "TRCK: 03", expected: map[string][]string{"TRCK": {"03"}}},
+		{title: "yaml null", name: "tags.yaml", input: "TALB: ~", expected: map[string][]string{"TALB": {""}}},
+		{title: "yaml date", name: "tags.yaml", input: "TDRC: 2021-05-01", expected: map[string][]string{"TDRC": {"2021-05-01"}}},
+		{title: "yaml list", name: "tags.yaml", input: "TPE1:\n  - One\n  - Two", expected: map[string][]string{"TPE1": {"One", "Two"}}},
+		{title: "yaml qualified key", name: "tags.yaml", input: "'TXXX:ASIN': B000", expected: map[string][]string{"TXXX:ASIN": {"B000"}}},
+		{title: "yaml empty", name: "tags.yaml", input: "", expected: map[string][]string{}},
+	} {
+		f := f // pin
+		t.Run(f.title, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := Decode(f.name, strings.NewReader(f.input))
+			if assert.NoError(t, err) {
+				assert.Equal(t, f.expected, result, "Input: '%s'", f.input)
+			}
+		})
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []struct {
+		title string
+		name  string
+		input string
+	}{
+		{title: "key value without separator", name: "tags.txt", input: "TALB"},
+		{title: "key value without key", name: "tags.txt", input: "=Album"},
+		{title: "json syntax", name: "tags.json", input: `{"TALB": }`},
+		{title: "json nested object", name: "tags.json", input: `{"TALB": {"a": "b"}}`},
+		{title: "yaml syntax", name: "tags.yaml", input: "TALB: [Album"},
+		{title: "yaml nested map", name: "tags.yaml", input: "TALB:\n  a: b"},
+	} {
+		f := f // pin
+		t.Run(f.title, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Decode(f.name, strings.NewReader(f.input))
+			assert.ErrorIs(t, err, ErrInvalidFormat)
+		})
+	}
+}
+
+func TestDecodeYAMLNode(t *testing.T) {
+	t.Parallel()
+
+	var document struct {
+		Tags yaml.Node `yaml:"tags"`
+	}
+	err := yaml.Unmarshal([]byte("tags:\n  TALB: Album\n  TPE1: [One, Two]\n"), &document)
+	if assert.NoError(t, err) {
+		tags, err := DecodeYAMLNode(&document.Tags)
+		if assert.NoError(t, err) {
+			assert.Equal(t, map[string][]string{"TALB": {"Album"}, "TPE1": {"One", "Two"}}, tags)
+		}
+	}
+
+	tags, err := DecodeYAMLNode(&yaml.Node{})
+	if assert.NoError(t, err) {
+		assert.Empty(t, tags)
+	}
+}
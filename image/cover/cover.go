@@ -0,0 +1,147 @@
+// Package cover prepares images for embedding as artwork: it resizes, converts and
+// strips metadata (e.g. EXIF) using the image packages of the standard library.
+package cover
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	ErrInvalidImage      = errors.New("invalid image")
+)
+
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+
+	MimeTypeJPEG = "image/jpeg"
+	MimeTypePNG  = "image/png"
+
+	jpegQuality = 90
+)
+
+// Formats are the formats an image can be converted to.
+var Formats = []string{FormatJPEG, FormatPNG}
+
+// Options describe the processing of an image. The zero value keeps the image as it is.
+type Options struct {
+	// MaxSize limits the longer side of the image in pixels. Smaller images are not enlarged.
+	MaxSize int
+	// Format is the format of the processed image (e.g. 'jpeg'). Empty keeps the format.
+	Format string
+}
+
+// ParseFormat returns the format for a name (e.g. 'jpg' or 'JPEG').
+func ParseFormat(name string) (string, error) {
+	switch strings.ToLower(name) {
+	case "":
+		return "", nil
+	case "jpeg", "jpg":
+		return FormatJPEG, nil
+	case "png":
+		return FormatPNG, nil
+	default:
+		return "", fmt.Errorf("'%s', supported: %s: %w", name, strings.Join(Formats, ", "), ErrUnsupportedFormat)
+	}
+}
+
+// Process reads an image with its MIME type and returns the processed image with its MIME type.
+// Images that are resized or have a format set are re-encoded (e.g. a progressive JPEG becomes a
+// baseline JPEG), which drops all metadata. JPEG images that are kept are stripped of their
+// metadata without re-encoding. Images other than JPEG and PNG (e.g. WebP) are converted to PNG,
+// if no format is set.
+func Process(r io.Reader, mimeType string, o Options) ([]byte, string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, "", err
+	}
+
+	format := o.Format
+	if format == "" {
+		format = formatOf(mimeType)
+	}
+
+	if format == "" {
+		// images other than JPEG and PNG are not supported by most players
+		format = FormatPNG
+	}
+
+	// an explicit format is always encoded by the standard library (e.g. for players that
+	// only support baseline JPEGs)
+	reencode := o.Format != "" || format != formatOf(mimeType)
+
+	if o.MaxSize > 0 || reencode {
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, "", fmt.Errorf("%v: %w", err, ErrInvalidImage)
+		}
+
+		if resized := resize(img, o.MaxSize); resized != img || reencode {
+			return encode(resized, format)
+		}
+	}
+
+	// keep the original encoding
+	if mimeType == MimeTypeJPEG {
+		return stripJPEGMetadata(data), mimeType, nil
+	}
+
+	return data, mimeType, nil
+}
+
+func encode(img image.Image, format string) ([]byte, string, error) {
+	var b bytes.Buffer
+
+	switch format {
+	case FormatJPEG:
+		// the encoder of the standard library writes baseline JPEGs
+		if err := jpeg.Encode(&b, opaque(img), &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, "", err
+		}
+
+		return b.Bytes(), MimeTypeJPEG, nil
+	case FormatPNG:
+		if err := png.Encode(&b, img); err != nil {
+			return nil, "", err
+		}
+
+		return b.Bytes(), MimeTypePNG, nil
+	default:
+		return nil, "", fmt.Errorf("'%s': %w", format, ErrUnsupportedFormat)
+	}
+}
+
+func formatOf(mimeType string) string {
+	switch mimeType {
+	case MimeTypeJPEG:
+		return FormatJPEG
+	case MimeTypePNG:
+		return FormatPNG
+	default:
+		return ""
+	}
+}
+
+// opaque draws an image with transparency on a white background, as JPEG has no alpha channel.
+func opaque(img image.Image) image.Image {
+	if o, ok := img.(interface{ Opaque() bool }); ok && o.Opaque() {
+		return img
+	}
+
+	b := img.Bounds()
+	dst := image.NewRGBA(b)
+	draw.Draw(dst, b, image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(dst, b, img, b.Min, draw.Over)
+
+	return dst
+}
@@ -0,0 +1,201 @@
+package cover
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestImage(width, height int, c color.Color) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, c)
+		}
+	}
+
+	return img
+}
+
+func encodePNG(img image.Image) []byte {
+	var b bytes.Buffer
+	png.Encode(&b, img)
+
+	return b.Bytes()
+}
+
+func encodeJPEG(img image.Image) []byte {
+	var b bytes.Buffer
+	jpeg.Encode(&b, img, nil)
+
+	return b.Bytes()
+}
+
+// withExif inserts an EXIF segment after the start of image marker.
+func withExif(data []byte) []byte {
+	exif := []byte{markerPrefix, markerApp1, 0x00, 0x0A, 'E', 'x', 'i', 'f', 0x00, 0x00, 0x01, 0x02}
+
+	return append(append(append([]byte{}, data[:2]...), exif...), data[2:]...)
+}
+
+func decodeConfig(t *testing.T, data []byte) (image.Config, string) {
+	t.Helper()
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	assert.NoError(t, err)
+
+	return config, format
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	for input, expected := range map[string]string{"": "", "jpeg": FormatJPEG, "JPG": FormatJPEG, "png": FormatPNG} {
+		format, err := ParseFormat(input)
+		if assert.NoError(t, err) {
+			assert.Equal(t, expected, format)
+		}
+	}
+
+	_, err := ParseFormat("tiff")
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestProcessKeepsImage(t *testing.T) {
+	t.Parallel()
+	data := encodePNG(newTestImage(20, 10, color.White))
+
+	processed, mimeType, err := Process(bytes.NewReader(data), MimeTypePNG, Options{MaxSize: 20})
+	if assert.NoError(t, err) {
+		assert.Equal(t, data, processed)
+		assert.Equal(t, MimeTypePNG, mimeType)
+	}
+}
+
+func TestProcessResizes(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []struct {
+		title          string
+		width, height  int
+		maxSize        int
+		expectedWidth  int
+		expectedHeight int
+	}{
+		{title: "landscape", width: 40, height: 20, maxSize: 10, expectedWidth: 10, expectedHeight: 5},
+		{title: "portrait", width: 20, height: 40, maxSize: 10, expectedWidth: 5, expectedHeight: 10},
+		{title: "square", width: 30, height: 30, maxSize: 7, expectedWidth: 7, expectedHeight: 7},
+		{title: "thin", width: 100, height: 1, maxSize: 10, expectedWidth: 10, expectedHeight: 1},
+	} {
+		f := f // pin
+		t.Run(f.title, func(t *testing.T) {
+			t.Parallel()
+			data := encodePNG(newTestImage(f.width, f.height, color.NRGBA{R: 200, A: 255}))
+
+			processed, mimeType, err := Process(bytes.NewReader(data), MimeTypePNG, Options{MaxSize: f.maxSize})
+			if assert.NoError(t, err) {
+				assert.Equal(t, MimeTypePNG, mimeType)
+
+				config, format := decodeConfig(t, processed)
+				assert.Equal(t, "png", format)
+				assert.Equal(t, f.expectedWidth, config.Width)
+				assert.Equal(t, f.expectedHeight, config.Height)
+			}
+		})
+	}
+}
+
+func TestProcessConvertsToJPEG(t *testing.T) {
+	t.Parallel()
+	data := encodePNG(newTestImage(8, 8, color.NRGBA{A: 0}))
+
+	processed, mimeType, err := Process(bytes.NewReader(data), MimeTypePNG, Options{Format: FormatJPEG})
+	if assert.NoError(t, err) {
+		assert.Equal(t, MimeTypeJPEG, mimeType)
+
+		img, err := jpeg.Decode(bytes.NewReader(processed))
+		if assert.NoError(t, err) {
+			// transparent pixels are drawn on white
+			r, g, b, _ := img.At(4, 4).RGBA()
+			assert.Greater(t, r, uint32(0xF000))
+			assert.Greater(t, g, uint32(0xF000))
+			assert.Greater(t, b, uint32(0xF000))
+		}
+	}
+}
+
+// progressiveJPEG is an 8x8 grayscale progressive JPEG (SOF2) with a single DC scan.
+var progressiveJPEG = func() []byte {
+	b := []byte{markerPrefix, markerStartOfImage}
+	// quantization table
+	b = append(b, 0xFF, 0xDB, 0x00, 0x43, 0x00)
+	b = append(b, bytes.Repeat([]byte{1}, 64)...)
+	// progressive frame: 8 bits, 8x8 pixels, one component
+	b = append(b, 0xFF, 0xC2, 0x00, 0x0B, 0x08, 0x00, 0x08, 0x00, 0x08, 0x01, 0x01, 0x11, 0x00)
+	// huffman table of the DC coefficients with a single code
+	b = append(b, 0xFF, 0xC4, 0x00, 0x14, 0x00, 0x01)
+	b = append(b, make([]byte, 16)...)
+	// scan of the DC coefficients and the end of the image
+	return append(b, 0xFF, 0xDA, 0x00, 0x08, 0x01, 0x01, 0x00, 0x00, 0x00, 0x00, 0x7F, 0xFF, 0xD9)
+}()
+
+func TestProcessConvertsProgressiveJPEG(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []struct {
+		title       string
+		options     Options
+		progressive bool
+	}{
+		{title: "kept", options: Options{}, progressive: true},
+		{title: "format set", options: Options{Format: FormatJPEG}, progressive: false},
+	} {
+		f := f // pin
+		t.Run(f.title, func(t *testing.T) {
+			t.Parallel()
+
+			processed, mimeType, err := Process(bytes.NewReader(progressiveJPEG), MimeTypeJPEG, f.options)
+			if assert.NoError(t, err) {
+				assert.Equal(t, MimeTypeJPEG, mimeType)
+				assert.Equal(t, f.progressive, bytes.Contains(processed, []byte{0xFF, 0xC2}))
+				assert.Equal(t, !f.progressive, bytes.Contains(processed, []byte{0xFF, 0xC0}))
+
+				config, format := decodeConfig(t, processed)
+				assert.Equal(t, "jpeg", format)
+				assert.Equal(t, 8, config.Width)
+				assert.Equal(t, 8, config.Height)
+			}
+		})
+	}
+}
+
+func TestProcessStripsExif(t *testing.T) {
+	t.Parallel()
+	data := encodeJPEG(newTestImage(8, 8, color.White))
+
+	processed, mimeType, err := Process(bytes.NewReader(withExif(data)), MimeTypeJPEG, Options{})
+	if assert.NoError(t, err) {
+		assert.Equal(t, MimeTypeJPEG, mimeType)
+		assert.Equal(t, data, processed)
+	}
+}
+
+func TestProcessInvalidImage(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := Process(bytes.NewReader([]byte("no image")), MimeTypePNG, Options{MaxSize: 10})
+	assert.ErrorIs(t, err, ErrInvalidImage)
+}
+
+func TestStripJPEGMetadataKeepsInvalidData(t *testing.T) {
+	t.Parallel()
+
+	for _, data := range [][]byte{{}, {0xFF}, []byte("no image"), {markerPrefix, markerStartOfImage, markerPrefix, markerApp1, 0xFF, 0xFF}} {
+		assert.Equal(t, data, stripJPEGMetadata(data))
+	}
+}
@@ -0,0 +1,56 @@
+package cover
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// JPEG markers, see: https://www.w3.org/Graphics/JPEG/itu-t81.pdf
+const (
+	markerPrefix       = 0xFF
+	markerStartOfImage = 0xD8
+	markerStartOfScan  = 0xDA
+	markerApp1         = 0xE1 // EXIF or XMP
+	markerApp13        = 0xED // Photoshop (IPTC)
+	markerComment      = 0xFE
+)
+
+// stripJPEGMetadata removes the segments holding metadata (EXIF, XMP, IPTC and comments)
+// from a JPEG without re-encoding it. Segments needed for decoding (e.g. color profiles)
+// are kept. Data that can't be parsed is returned as it is.
+func stripJPEGMetadata(data []byte) []byte {
+	if len(data) < 2 || data[0] != markerPrefix || data[1] != markerStartOfImage {
+		return data
+	}
+
+	var stripped bytes.Buffer
+	stripped.Write(data[:2])
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != markerPrefix {
+			return data
+		}
+
+		marker := data[i+1]
+		if marker == markerStartOfScan {
+			// the compressed image data follows until the end
+			stripped.Write(data[i:])
+			return stripped.Bytes()
+		}
+
+		end := i + 2 + int(binary.BigEndian.Uint16(data[i+2:i+4]))
+		if end > len(data) {
+			return data
+		}
+
+		switch marker {
+		case markerApp1, markerApp13, markerComment:
+		default:
+			stripped.Write(data[i:end])
+		}
+
+		i = end
+	}
+
+	return data
+}
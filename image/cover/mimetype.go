@@ -0,0 +1,76 @@
+package cover
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	// register the decoders of the supported images
+	_ "image/gif"
+
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	MimeTypeGIF  = "image/gif"
+	MimeTypeBMP  = "image/bmp"
+	MimeTypeWebP = "image/webp"
+
+	// sniffLength is the number of bytes needed to detect the type of an image
+	sniffLength = 12
+)
+
+// signature identifies an image by its magic bytes at the start of the file. Bytes set to
+// the wildcard are ignored (e.g. the size in the header of a RIFF container).
+type signature struct {
+	magic    []byte
+	mimeType string
+}
+
+const wildcard = '?'
+
+var signatures = []signature{
+	{magic: []byte("\xFF\xD8\xFF"), mimeType: MimeTypeJPEG},
+	{magic: []byte("\x89PNG\r\n\x1A\n"), mimeType: MimeTypePNG},
+	{magic: []byte("GIF87a"), mimeType: MimeTypeGIF},
+	{magic: []byte("GIF89a"), mimeType: MimeTypeGIF},
+	{magic: []byte("BM"), mimeType: MimeTypeBMP},
+	{magic: []byte("RIFF????WEBP"), mimeType: MimeTypeWebP},
+}
+
+// SupportedTypes are the names of the images that can be detected.
+var SupportedTypes = []string{"jpeg", "png", "gif", "bmp", "webp"}
+
+// DetectMimeType returns the MIME type of an image by the magic bytes at its start.
+// Data that is not a supported image is rejected.
+func DetectMimeType(r io.Reader) (string, error) {
+	header := make([]byte, sniffLength)
+	n, err := io.ReadFull(r, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	header = header[:n]
+
+	for _, s := range signatures {
+		if matches(header, s.magic) {
+			return s.mimeType, nil
+		}
+	}
+
+	return "", fmt.Errorf("not a supported image (%s): %w", strings.Join(SupportedTypes, ", "), ErrUnsupportedFormat)
+}
+
+func matches(header, magic []byte) bool {
+	if len(header) < len(magic) {
+		return false
+	}
+
+	for i, b := range magic {
+		if b != wildcard && header[i] != b {
+			return false
+		}
+	}
+
+	return true
+}
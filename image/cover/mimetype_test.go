@@ -0,0 +1,67 @@
+package cover
+
+import (
+	"bytes"
+	"image/color"
+	"image/gif"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/image/bmp"
+)
+
+func TestDetectMimeType(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []struct {
+		title    string
+		input    string
+		expected string
+	}{
+		{title: "jpeg", input: "\xFF\xD8\xFF\xE0\x00\x10JFIF", expected: MimeTypeJPEG},
+		{title: "png", input: "\x89PNG\r\n\x1A\n\x00\x00\x00\x0D", expected: MimeTypePNG},
+		{title: "gif87a", input: "GIF87a\x01\x00", expected: MimeTypeGIF},
+		{title: "gif89a", input: "GIF89a\x01\x00", expected: MimeTypeGIF},
+		{title: "bmp", input: "BM\x46\x00\x00\x00", expected: MimeTypeBMP},
+		{title: "webp", input: "RIFF\x1A\x00\x00\x00WEBPVP8L", expected: MimeTypeWebP},
+	} {
+		f := f // pin
+		t.Run(f.title, func(t *testing.T) {
+			t.Parallel()
+
+			mimeType, err := DetectMimeType(strings.NewReader(f.input))
+			if assert.NoError(t, err) {
+				assert.Equal(t, f.expected, mimeType)
+			}
+		})
+	}
+}
+
+func TestDetectMimeTypeRejectsNonImages(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{"", "\xFF", "text file", "RIFF\x1A\x00\x00\x00WAVEfmt ", "ID3\x04\x00"} {
+		_, err := DetectMimeType(strings.NewReader(input))
+		assert.ErrorIs(t, err, ErrUnsupportedFormat, "Input: '%s'", input)
+	}
+}
+
+func TestProcessConvertsToPNG(t *testing.T) {
+	t.Parallel()
+	img := newTestImage(4, 4, color.NRGBA{G: 255, A: 255})
+
+	var g, b bytes.Buffer
+	assert.NoError(t, gif.Encode(&g, img, nil))
+	assert.NoError(t, bmp.Encode(&b, img))
+
+	for mimeType, data := range map[string][]byte{MimeTypeGIF: g.Bytes(), MimeTypeBMP: b.Bytes()} {
+		processed, processedMimeType, err := Process(bytes.NewReader(data), mimeType, Options{})
+		if assert.NoError(t, err, mimeType) {
+			assert.Equal(t, MimeTypePNG, processedMimeType)
+
+			_, format := decodeConfig(t, processed)
+			assert.Equal(t, "png", format)
+		}
+	}
+}
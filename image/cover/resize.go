@@ -0,0 +1,70 @@
+package cover
+
+import (
+	"image"
+	"image/color"
+)
+
+// resize scales an image down so that its longer side does not exceed maxSize. The image
+// is returned as it is, if it is already small enough or maxSize is not set.
+func resize(img image.Image, maxSize int) image.Image {
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+
+	if maxSize <= 0 || (width <= maxSize && height <= maxSize) {
+		return img
+	}
+
+	newWidth, newHeight := maxSize, maxSize
+	if width > height {
+		newHeight = atLeast(height*maxSize/width, 1)
+	} else {
+		newWidth = atLeast(width*maxSize/height, 1)
+	}
+
+	return boxFilter(img, newWidth, newHeight)
+}
+
+// boxFilter scales an image down by averaging the pixels of the source that are covered
+// by a pixel of the destination (area averaging).
+func boxFilter(img image.Image, width, height int) *image.RGBA {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := b.Min.Y + y*b.Dy()/height
+		y1 := atLeast(b.Min.Y+(y+1)*b.Dy()/height, y0+1)
+
+		for x := 0; x < width; x++ {
+			x0 := b.Min.X + x*b.Dx()/width
+			x1 := atLeast(b.Min.X+(x+1)*b.Dx()/width, x0+1)
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+
+			// RGBA returns 16 bit values (premultiplied alpha)
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(bl / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+
+	return dst
+}
+
+func atLeast(v, min int) int {
+	if v < min {
+		return min
+	}
+
+	return v
+}
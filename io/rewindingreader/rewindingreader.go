@@ -31,10 +31,17 @@ func (r *rewindingReader) Read(p []byte) (int, error) {
 
 	n, err := r.readSeeker.Read(p)
 
-	l := len(p)
-	if (l > 0 && n < l) || (err != nil && errors.Is(err, io.EOF)) {
+	// a short read is not the end of the stream, a consumer that fills its buffer (e.g. 'io.ReadFull')
+	// would continue with the beginning of the stream
+	if err != nil && errors.Is(err, io.EOF) {
 		r.rewind = true
 	}
 
 	return n, err
 }
+
+// Rewind resets the read cursor to the beginning for the next read (e.g. for a consumer that
+// stops before the end of the stream).
+func (r *rewindingReader) Rewind() {
+	r.rewind = true
+}
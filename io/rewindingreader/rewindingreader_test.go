@@ -0,0 +1,91 @@
+package rewindingreader
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const content = "abcdef"
+
+func TestRewindsAfterTheEnd(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []struct {
+		name   string
+		reader io.ReadSeeker
+	}{
+		{name: "eof after the data", reader: bytes.NewReader([]byte(content))},
+		{name: "eof with the data", reader: &dataErrReadSeeker{bytes.NewReader([]byte(content))}},
+	} {
+		f := f // pin
+		t.Run(f.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := New(f.reader)
+
+			for i := 0; i < 2; i++ {
+				data, err := io.ReadAll(r)
+				if assert.NoError(t, err) {
+					assert.Equal(t, content, string(data))
+				}
+			}
+		})
+	}
+}
+
+func TestShortReadDoesNotRewind(t *testing.T) {
+	t.Parallel()
+
+	r := New(bytes.NewReader([]byte(content)))
+	buffer := make([]byte, 4)
+
+	n, err := io.ReadFull(r, buffer)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "abcd", string(buffer[:n]))
+	}
+
+	// the rest is shorter than the buffer, the buffer is not filled from the beginning
+	n, err = io.ReadFull(r, buffer)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Equal(t, "ef", string(buffer[:n]))
+
+	// the next consumer starts at the beginning
+	n, err = io.ReadFull(r, buffer)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "abcd", string(buffer[:n]))
+	}
+}
+
+// dataErrReadSeeker returns io.EOF together with the last data.
+type dataErrReadSeeker struct {
+	*bytes.Reader
+}
+
+func (r *dataErrReadSeeker) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err == nil && r.Len() == 0 {
+		err = io.EOF
+	}
+
+	return n, err
+}
+
+func TestRewind(t *testing.T) {
+	t.Parallel()
+
+	r := New(bytes.NewReader([]byte(content)))
+	buffer := make([]byte, 2)
+	if _, err := io.ReadFull(r, buffer); !assert.NoError(t, err) {
+		return
+	}
+
+	r.(interface{ Rewind() }).Rewind()
+
+	data, err := io.ReadAll(r)
+	if assert.NoError(t, err) {
+		assert.Equal(t, content, string(data))
+	}
+}
@@ -0,0 +1,114 @@
+package mp3binder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/crra/id3v2/v2"
+)
+
+const (
+	tagPicture     = "APIC"
+	tagUserURL     = "WXXX"
+	subFrameHeader = 10
+)
+
+// subFrame is a frame embedded in a chapter frame.
+type subFrame struct {
+	id    string
+	frame id3v2.Framer
+}
+
+// chapterFrame extends the chapter frame of the id3v2 library, which only writes the
+// title and the description, with additional sub-frames (e.g. pictures and links).
+type chapterFrame struct {
+	id3v2.ChapterFrame
+	subFrames []subFrame
+}
+
+// Size implements the id3v2.Framer interface.
+func (cf chapterFrame) Size() int {
+	size := cf.ChapterFrame.Size()
+	for _, sf := range cf.subFrames {
+		size += subFrameHeader + sf.frame.Size()
+	}
+
+	return size
+}
+
+// WriteTo implements the id3v2.Framer interface.
+func (cf chapterFrame) WriteTo(w io.Writer) (int64, error) {
+	n, err := cf.ChapterFrame.WriteTo(w)
+	if err != nil {
+		return n, err
+	}
+
+	for _, sf := range cf.subFrames {
+		header := make([]byte, subFrameHeader)
+		copy(header, sf.id)
+		// the chapter frame of the id3v2 library writes its sub-frames synchsafe (id3v2.4)
+		binary.BigEndian.PutUint32(header[4:8], synchsafe(uint32(sf.frame.Size())))
+
+		written, err := w.Write(header)
+		n += int64(written)
+		if err != nil {
+			return n, err
+		}
+
+		body, err := sf.frame.WriteTo(w)
+		n += body
+		if err != nil {
+			return n, err
+		}
+	}
+
+	return n, nil
+}
+
+// synchsafe encodes an integer with 7 bits per byte as used for sizes in id3v2.4.
+func synchsafe(size uint32) uint32 {
+	return (size & 0x7F) | (size&0x3F80)<<1 | (size&0x1FC000)<<2 | (size&0xFE00000)<<3
+}
+
+// frontCoverOf returns the front cover picture of a tag, if present.
+func frontCoverOf(tag *id3v2.Tag) *id3v2.PictureFrame {
+	if tag == nil {
+		return nil
+	}
+
+	for _, f := range tag.GetFrames(tagPicture) {
+		if pf, ok := f.(id3v2.PictureFrame); ok && pf.PictureType == id3v2.PTFrontCover {
+			return &pf
+		}
+	}
+
+	return nil
+}
+
+// chapterArtwork returns the front cover and the links of an input file as sub-frames
+// for its chapter. A picture identical to the cover of the output file or to the picture of the
+// previous chapter is skipped to not embed the same image repeatedly.
+func chapterArtwork(input, output *id3v2.Tag, previous *id3v2.PictureFrame) ([]subFrame, *id3v2.PictureFrame) {
+	var subFrames []subFrame
+
+	picture := frontCoverOf(input)
+	if picture != nil {
+		cover := frontCoverOf(output)
+
+		switch {
+		case cover != nil && bytes.Equal(cover.Picture, picture.Picture):
+		case previous != nil && bytes.Equal(previous.Picture, picture.Picture):
+		default:
+			subFrames = append(subFrames, subFrame{id: tagPicture, frame: *picture})
+		}
+	}
+
+	if input != nil {
+		for _, f := range input.GetFrames(tagUserURL) {
+			subFrames = append(subFrames, subFrame{id: tagUserURL, frame: f})
+		}
+	}
+
+	return subFrames, picture
+}
@@ -0,0 +1,156 @@
+package mp3binder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/crra/id3v2/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// newPicture returns a front cover with the content.
+func newPicture(content string) *id3v2.PictureFrame {
+	return &id3v2.PictureFrame{
+		Encoding:    id3v2.EncodingUTF8,
+		MimeType:    "image/png",
+		PictureType: id3v2.PTFrontCover,
+		Description: "Cover",
+		Picture:     []byte(content),
+	}
+}
+
+// newTagWithPicture returns a tag with the picture, a nil picture returns an empty tag.
+func newTagWithPicture(picture *id3v2.PictureFrame) *id3v2.Tag {
+	tag := id3v2.NewEmptyTag()
+	if picture != nil {
+		tag.AddAttachedPicture(*picture)
+	}
+
+	return tag
+}
+
+func newChapterFrame(subFrames ...subFrame) chapterFrame {
+	return chapterFrame{
+		ChapterFrame: id3v2.ChapterFrame{
+			ElementID:   "ch0",
+			StartTime:   time.Second,
+			EndTime:     3 * time.Second,
+			StartOffset: id3v2.IgnoredOffset,
+			EndOffset:   id3v2.IgnoredOffset,
+			Title:       &id3v2.TextFrame{Encoding: id3v2.EncodingUTF8, Text: "Chapter 1"},
+		},
+		subFrames: subFrames,
+	}
+}
+
+func TestChapterFrameSize(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []struct {
+		name  string
+		frame chapterFrame
+	}{
+		{name: "without sub-frames", frame: newChapterFrame()},
+		{name: "with picture", frame: newChapterFrame(subFrame{id: tagPicture, frame: *newPicture("picture")})},
+		{name: "with picture and link", frame: newChapterFrame(
+			subFrame{id: tagPicture, frame: *newPicture("picture")},
+			subFrame{id: tagUserURL, frame: userDefinedURLFrame{Encoding: id3v2.EncodingUTF8, Description: "Shop", URL: "https://example.com"}},
+		)},
+	} {
+		f := f // pin
+		t.Run(f.name, func(t *testing.T) {
+			t.Parallel()
+
+			var b bytes.Buffer
+			n, err := f.frame.WriteTo(&b)
+			if assert.NoError(t, err) {
+				assert.Equal(t, int64(b.Len()), n)
+				assert.Equal(t, b.Len(), f.frame.Size())
+			}
+		})
+	}
+}
+
+func TestChapterFrameRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	picture := newPicture("picture")
+	cf := newChapterFrame(subFrame{id: tagPicture, frame: *picture})
+
+	tag := id3v2.NewEmptyTag()
+	tag.AddFrame(tagChapter, cf)
+
+	var b bytes.Buffer
+	if _, err := tag.WriteTo(&b); !assert.NoError(t, err) {
+		return
+	}
+
+	parsed, err := id3v2.ParseReader(bytes.NewReader(b.Bytes()), id3v2.Options{Parse: true})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	frames := parsed.GetFrames(tagChapter)
+	if assert.Len(t, frames, 1) {
+		chapter, ok := frames[0].(id3v2.ChapterFrame)
+		if assert.True(t, ok) {
+			assert.Equal(t, "ch0", chapter.ElementID)
+			assert.Equal(t, time.Second, chapter.StartTime)
+			assert.Equal(t, 3*time.Second, chapter.EndTime)
+			assert.Equal(t, "Chapter 1", chapter.Title.Text)
+		}
+	}
+
+	// the id3v2 library ignores the picture, it follows the title as a synchsafe sub-frame
+	var body bytes.Buffer
+	if _, err := picture.WriteTo(&body); !assert.NoError(t, err) {
+		return
+	}
+
+	header := make([]byte, subFrameHeader)
+	copy(header, tagPicture)
+	binary.BigEndian.PutUint32(header[4:8], synchsafe(uint32(body.Len())))
+	assert.True(t, bytes.HasSuffix(bytes.TrimRight(b.Bytes(), "\x00"), append(header, body.Bytes()...)))
+}
+
+func TestChapterArtwork(t *testing.T) {
+	t.Parallel()
+
+	picture := newPicture("picture")
+	link := userDefinedURLFrame{Encoding: id3v2.EncodingUTF8, Description: "Shop", URL: "https://example.com"}
+
+	for _, f := range []struct {
+		name     string
+		input    *id3v2.PictureFrame
+		cover    *id3v2.PictureFrame
+		previous *id3v2.PictureFrame
+		embedded bool
+	}{
+		{name: "no picture", input: nil},
+		{name: "picture", input: picture, embedded: true},
+		{name: "other cover and previous picture", input: picture, cover: newPicture("cover"), previous: newPicture("previous"), embedded: true},
+		{name: "cover of the output file", input: picture, cover: newPicture("picture")},
+		{name: "picture of the previous chapter", input: picture, previous: newPicture("picture")},
+	} {
+		f := f // pin
+		t.Run(f.name, func(t *testing.T) {
+			t.Parallel()
+
+			input := newTagWithPicture(f.input)
+			input.AddFrame(tagUserURL, link)
+
+			subFrames, previous := chapterArtwork(input, newTagWithPicture(f.cover), f.previous)
+
+			expected := []subFrame{{id: tagUserURL, frame: link}}
+			if f.embedded {
+				expected = append([]subFrame{{id: tagPicture, frame: *f.input}}, expected...)
+			}
+
+			assert.Equal(t, expected, subFrames)
+			// a skipped picture is still the previous picture of the next chapter
+			assert.Equal(t, f.input, previous)
+		})
+	}
+}
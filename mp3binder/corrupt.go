@@ -0,0 +1,73 @@
+package mp3binder
+
+import (
+	"io"
+	"time"
+
+	"github.com/dmulholl/mp3lib"
+)
+
+// Corruption is a region of an input file that is neither a frame nor a tag (e.g. garbage or a
+// truncated frame). The parser skips it until the next frame or tag.
+type Corruption struct {
+	// position in bytes from the start of the input file
+	Offset int64
+	Length int
+	// position in the audio of the input file
+	Position time.Duration
+}
+
+type corruptVisitor func(index int, corruptions []Corruption) error
+
+// DetectCorruption reports the regions of each input file that are skipped while binding. The
+// visitor receives the corruptions of an input file after it is bound, returning an error aborts
+// the binding.
+func DetectCorruption(visitor corruptVisitor) Option {
+	return func() (stage, string, jobProcessor) {
+		return stageInit, "detect corruption", func(j *job) error {
+			j.corruptVisitor = visitor
+
+			return nil
+		}
+	}
+}
+
+// corruptionDetector reads the objects of an input file and locates the bytes skipped between them.
+type corruptionDetector struct {
+	r io.Reader
+	// bytes read from the input file
+	read int64
+	// bytes read up to the end of the last object
+	parsed      int64
+	corruptions []Corruption
+}
+
+func (d *corruptionDetector) Read(p []byte) (int, error) {
+	n, err := d.r.Read(p)
+	d.read += int64(n)
+
+	return n, err
+}
+
+// next returns the next object of the input file (nil at its end) and records the bytes skipped
+// before it at the position.
+func (d *corruptionDetector) next(position time.Duration) any {
+	obj := mp3lib.NextObject(d)
+
+	var length int
+	switch obj := obj.(type) {
+	case *mp3lib.MP3Frame:
+		length = len(obj.RawBytes)
+	case *mp3lib.ID3v1Tag:
+		length = len(obj.RawBytes)
+	case *mp3lib.ID3v2Tag:
+		length = len(obj.RawBytes)
+	}
+
+	if skipped := d.read - int64(length) - d.parsed; skipped > 0 {
+		d.corruptions = append(d.corruptions, Corruption{Offset: d.parsed, Length: int(skipped), Position: position})
+	}
+	d.parsed = d.read
+
+	return obj
+}
@@ -0,0 +1,104 @@
+package mp3binder
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/dmulholl/mp3lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorruptionDetector(t *testing.T) {
+	t.Parallel()
+
+	frame := makeFrame(t, header44100)
+	frameDuration := duration(frame)
+	garbage := bytes.Repeat([]byte{0x55}, 10)
+
+	for _, f := range []struct {
+		name     string
+		input    []byte
+		frames   int
+		expected []Corruption
+	}{
+		{name: "valid", input: stream(repeat(frame, 3)...), frames: 3},
+		{
+			name:     "leading garbage",
+			input:    append(append([]byte{}, garbage...), stream(repeat(frame, 2)...)...),
+			frames:   2,
+			expected: []Corruption{{Offset: 0, Length: 10, Position: 0}},
+		},
+		{
+			name:     "garbage between frames",
+			input:    append(append(stream(frame), garbage...), stream(repeat(frame, 2)...)...),
+			frames:   3,
+			expected: []Corruption{{Offset: 417, Length: 10, Position: frameDuration}},
+		},
+		{
+			name:     "truncated frame",
+			input:    stream(repeat(frame, 3)...)[:2*417+100],
+			frames:   2,
+			expected: []Corruption{{Offset: 2 * 417, Length: 100, Position: 2 * frameDuration}},
+		},
+		{
+			name:   "garbage and truncated frame",
+			input:  append(append(stream(frame), garbage...), stream(repeat(frame, 3)...)[:417+200]...),
+			frames: 2,
+			expected: []Corruption{
+				{Offset: 417, Length: 10, Position: frameDuration},
+				{Offset: 2*417 + 10, Length: 200, Position: 2 * frameDuration},
+			},
+		},
+	} {
+		f := f // pin
+		t.Run(f.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := &corruptionDetector{r: bytes.NewReader(f.input)}
+
+			var frames int
+			for {
+				obj := d.next(time.Duration(frames) * frameDuration)
+				if obj == nil {
+					break
+				}
+
+				_, ok := obj.(*mp3lib.MP3Frame)
+				assert.True(t, ok)
+				frames++
+			}
+
+			assert.Equal(t, f.frames, frames)
+			assert.Equal(t, f.expected, d.corruptions)
+		})
+	}
+}
+
+func TestDetectCorruption(t *testing.T) {
+	t.Parallel()
+
+	frame := makeFrame(t, header44100)
+	valid := stream(repeat(frame, 2)...)
+	corrupt := append(append(stream(frame), 0x55, 0x55, 0x55), stream(frame)...)
+
+	var indexes []int
+	var found []Corruption
+	output, err := bind(t, [][]byte{valid, corrupt}, DetectCorruption(func(index int, corruptions []Corruption) error {
+		indexes = append(indexes, index)
+		found = append(found, corruptions...)
+
+		return nil
+	}))
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, []int{1}, indexes)
+		assert.Equal(t, []Corruption{{Offset: 417, Length: 3, Position: duration(frame)}}, found)
+		assert.Len(t, audioFrames(t, output), 4)
+	}
+
+	_, err = bind(t, [][]byte{corrupt, valid}, DetectCorruption(func(int, []Corruption) error {
+		return errTest
+	}))
+	assert.ErrorIs(t, err, errTest)
+}
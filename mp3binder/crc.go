@@ -0,0 +1,81 @@
+package mp3binder
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/dmulholl/mp3lib"
+)
+
+const (
+	crcPolynomial = 0x8005
+	crcInitial    = 0xFFFF
+	// the CRC follows the header
+	crcOffset = 4
+)
+
+type (
+	crcVisitor func(index int, positions []time.Duration)
+
+	// crcCheck verifies the CRC of the protected frames of the input files
+	crcCheck struct {
+		drop    bool
+		visitor crcVisitor
+	}
+)
+
+// VerifyCRC verifies the CRC of the Layer III frames of the input files that are protected by
+// one. The visitor receives the positions of the frames with a wrong CRC for each input file,
+// which are dropped if requested.
+func VerifyCRC(drop bool, visitor crcVisitor) Option {
+	return func() (stage, string, jobProcessor) {
+		return stageInit, "verify CRC", func(j *job) error {
+			j.crc = &crcCheck{drop: drop, visitor: visitor}
+
+			return nil
+		}
+	}
+}
+
+// hasValidCRC returns false if the CRC of a protected Layer III frame doesn't match the frame.
+// Unprotected frames and frames of other layers are always valid.
+func hasValidCRC(frame *mp3lib.MP3Frame) bool {
+	sideInfoSize := getSideInfoSize(frame)
+	if !frame.CrcProtection || sideInfoSize == 0 || len(frame.RawBytes) < sideInfoOffset(frame)+sideInfoSize {
+		return true
+	}
+
+	return binary.BigEndian.Uint16(frame.RawBytes[crcOffset:]) == frameCRC(frame)
+}
+
+// frameCRC calculates the CRC-16 of a Layer III frame, which covers the last two bytes of the
+// header and the side information.
+func frameCRC(frame *mp3lib.MP3Frame) uint16 {
+	crc := uint16(crcInitial)
+	update := func(data []byte) {
+		for _, b := range data {
+			for i := 7; i >= 0; i-- {
+				bit := (b>>i)&1 == 1
+				msb := crc&0x8000 != 0
+				crc <<= 1
+				if bit != msb {
+					crc ^= crcPolynomial
+				}
+			}
+		}
+	}
+
+	update(frame.RawBytes[2:4])
+	update(frame.RawBytes[crcOffset+2 : crcOffset+2+getSideInfoSize(frame)])
+
+	return crc
+}
+
+// updateCRC writes the CRC of a protected Layer III frame (e.g. after changing the side information).
+func updateCRC(frame *mp3lib.MP3Frame) {
+	if !frame.CrcProtection || getSideInfoSize(frame) == 0 {
+		return
+	}
+
+	binary.BigEndian.PutUint16(frame.RawBytes[crcOffset:], frameCRC(frame))
+}
@@ -0,0 +1,104 @@
+package mp3binder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dmulholl/mp3lib"
+	"github.com/stretchr/testify/assert"
+)
+
+// flipBit returns a copy of the frame with the bit at the position flipped.
+func flipBit(t *testing.T, frame *mp3lib.MP3Frame, pos int) *mp3lib.MP3Frame {
+	t.Helper()
+
+	flipped := *frame
+	flipped.RawBytes = append([]byte{}, frame.RawBytes...)
+	flipped.RawBytes[pos/8] ^= 1 << (7 - pos%8)
+
+	return &flipped
+}
+
+func TestHasValidCRC(t *testing.T) {
+	t.Parallel()
+
+	protected := makeAudibleFrame(t, header44100CRC, 1000, 100, 150)
+	unprotected := makeAudibleFrame(t, header44100, 1000, 100, 150)
+
+	for _, f := range []struct {
+		name     string
+		frame    *mp3lib.MP3Frame
+		expected bool
+	}{
+		{name: "valid", frame: protected, expected: true},
+		{name: "flipped bit in the header", frame: flipBit(t, protected, 3*8+4), expected: false},
+		{name: "flipped bit in the CRC", frame: flipBit(t, protected, crcOffset*8+15), expected: false},
+		{name: "flipped bit in the side information", frame: flipBit(t, protected, (crcOffset+2)*8+20), expected: false},
+		{name: "flipped bit in the main data", frame: flipBit(t, protected, (crcOffset+2+32)*8), expected: true},
+		{name: "unprotected", frame: flipBit(t, unprotected, 4*8+20), expected: true},
+	} {
+		f := f // pin
+		t.Run(f.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, f.expected, hasValidCRC(f.frame))
+		})
+	}
+}
+
+func TestUpdateCRC(t *testing.T) {
+	t.Parallel()
+
+	frame := flipBit(t, makeAudibleFrame(t, header44100CRC, 1000, 100, 150), (crcOffset+2)*8+20)
+	assert.False(t, hasValidCRC(frame))
+
+	updateCRC(frame)
+	assert.True(t, hasValidCRC(frame))
+
+	unprotected := makeFrame(t, header44100)
+	before := append([]byte{}, unprotected.RawBytes...)
+	updateCRC(unprotected)
+	assert.Equal(t, before, unprotected.RawBytes, "the side information follows the header")
+}
+
+func TestVerifyCRC(t *testing.T) {
+	t.Parallel()
+
+	valid := makeAudibleFrame(t, header44100CRC, 1000, 100, 150)
+	invalid := flipBit(t, valid, (crcOffset+2)*8+20)
+	frameDuration := duration(valid)
+
+	input := stream(valid, invalid, valid, valid, invalid)
+
+	for _, f := range []struct {
+		name     string
+		drop     bool
+		expected [][]byte
+	}{
+		{name: "keep", drop: false, expected: [][]byte{valid.RawBytes, invalid.RawBytes, valid.RawBytes, valid.RawBytes, invalid.RawBytes}},
+		{name: "drop", drop: true, expected: [][]byte{valid.RawBytes, valid.RawBytes, valid.RawBytes}},
+	} {
+		f := f // pin
+		t.Run(f.name, func(t *testing.T) {
+			t.Parallel()
+
+			var indexes []int
+			var positions []time.Duration
+			output, err := bind(t, [][]byte{stream(valid), input}, VerifyCRC(f.drop, func(index int, p []time.Duration) {
+				indexes = append(indexes, index)
+				positions = append(positions, p...)
+			}))
+			if assert.NoError(t, err) {
+				assert.Equal(t, []int{1}, indexes)
+				assert.Equal(t, []time.Duration{frameDuration, 4 * frameDuration}, positions)
+
+				frames := audioFrames(t, output)[1:]
+				if assert.Len(t, frames, len(f.expected)) {
+					for i := range frames {
+						assert.Equal(t, f.expected[i], frames[i].RawBytes, "frame %d", i)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package mp3binder
+
+import "time"
+
+// rewinder is an input file that can be read again from the start (e.g. a file bound more than once).
+type rewinder interface {
+	Rewind()
+}
+
+// timeRange is a part of an input file. A zero end is the end of the file.
+type timeRange struct {
+	start time.Duration
+	end   time.Duration
+}
+
+// Cut binds only a part of the input files. The callback returns the start and the end of the part
+// of the input file at the index (zero for the end of the file). The input files are cut at the
+// frame boundaries nearest to the start and the end, the rest of an input file after the end is
+// not read.
+func Cut(rangeOf func(index int) (start, end time.Duration)) Option {
+	return func() (stage, string, jobProcessor) {
+		return stageInit, "cut", func(j *job) error {
+			j.rangeOf = func(index int) timeRange {
+				start, end := rangeOf(index)
+				return timeRange{start: start, end: end}
+			}
+
+			return nil
+		}
+	}
+}
+
+// includes returns true if the frame at the position with the duration belongs to the range,
+// which is the case if its middle is within the range.
+func (r timeRange) includes(position, d time.Duration) bool {
+	middle := position + d/2
+
+	return middle >= r.start && (r.end == 0 || middle < r.end)
+}
+
+// ended returns true if no frame at or after the position belongs to the range.
+func (r timeRange) ended(position time.Duration) bool {
+	return r.end > 0 && position >= r.end
+}
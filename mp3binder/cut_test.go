@@ -0,0 +1,154 @@
+package mp3binder
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/crra/id3v2/v2"
+	"github.com/crra/mp3binder/io/rewindingreader"
+	"github.com/dmulholl/mp3lib"
+	"github.com/stretchr/testify/assert"
+)
+
+// numberedFrames returns frames that differ by their global gain, which starts at 100.
+func numberedFrames(t *testing.T, count int) []*mp3lib.MP3Frame {
+	t.Helper()
+
+	frames := make([]*mp3lib.MP3Frame, count)
+	for i := range frames {
+		frames[i] = makeAudibleFrame(t, header44100, 1000, 100, 100+i)
+	}
+
+	return frames
+}
+
+// numbersOf returns the numbers of the numbered frames.
+func numbersOf(t *testing.T, frames []*mp3lib.MP3Frame) []int {
+	t.Helper()
+
+	numbers := make([]int, len(frames))
+	for i, f := range frames {
+		numbers[i] = globalGains(t, f)[0] - 100
+	}
+
+	return numbers
+}
+
+// cut returns a range for every input file.
+func cut(start, end time.Duration) Option {
+	return Cut(func(int) (time.Duration, time.Duration) { return start, end })
+}
+
+func TestCut(t *testing.T) {
+	t.Parallel()
+
+	frames := numberedFrames(t, 10)
+	d := duration(frames[0])
+
+	for _, f := range []struct {
+		name       string
+		start, end time.Duration
+		expected   []int
+	}{
+		{name: "whole file", expected: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{name: "start at the middle of a frame", start: d / 2, expected: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{name: "start after the middle of a frame", start: d/2 + 1, expected: []int{1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{name: "end at the middle of a frame", end: 2*d + d/2, expected: []int{0, 1}},
+		{name: "end after the middle of a frame", end: 2*d + d/2 + 1, expected: []int{0, 1, 2}},
+		{name: "open start", end: 3 * d, expected: []int{0, 1, 2}},
+		{name: "open end", start: 7 * d, expected: []int{7, 8, 9}},
+		{name: "start and end", start: 2 * d, end: 5 * d, expected: []int{2, 3, 4}},
+		{name: "after the end of the file", start: 20 * d, expected: []int{}},
+	} {
+		f := f // pin
+		t.Run(f.name, func(t *testing.T) {
+			t.Parallel()
+
+			output, err := bind(t, [][]byte{stream(frames...)}, cut(f.start, f.end))
+			if assert.NoError(t, err) {
+				assert.Equal(t, f.expected, numbersOf(t, audioFrames(t, output)))
+			}
+		})
+	}
+}
+
+func TestCutChapters(t *testing.T) {
+	t.Parallel()
+
+	frames := numberedFrames(t, 10)
+	d := duration(frames[0])
+
+	var durations []time.Duration
+	output, err := bind(t, [][]byte{stream(frames...), stream(frames...)},
+		Cut(func(index int) (time.Duration, time.Duration) {
+			if index == 0 {
+				return 2 * d, 5 * d
+			}
+
+			return 6 * d, 0
+		}),
+		Chapters(func(_, _ int, duration time.Duration) (bool, string) {
+			durations = append(durations, duration)
+			return true, ""
+		}))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// the durations of the bound parts
+	assert.Equal(t, []time.Duration{3 * d, 4 * d}, durations)
+
+	tag, err := id3v2.ParseReader(bytes.NewReader(output), id3v2.Options{Parse: true})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var chapters [][2]time.Duration
+	for _, f := range tag.GetFrames(tagChapter) {
+		if chapter, ok := f.(id3v2.ChapterFrame); ok {
+			chapters = append(chapters, [2]time.Duration{chapter.StartTime, chapter.EndTime})
+		}
+	}
+
+	// the chapter frame stores milliseconds
+	ms := func(d time.Duration) time.Duration { return d.Truncate(time.Millisecond) }
+	assert.Equal(t, [][2]time.Duration{{0, ms(3 * d)}, {ms(3 * d), ms(7 * d)}}, chapters)
+}
+
+func TestCutStopsAtTheEnd(t *testing.T) {
+	t.Parallel()
+
+	frames := numberedFrames(t, 5)
+	d := duration(frames[0])
+
+	// the garbage after the range is not read
+	input := append(stream(frames...), bytes.Repeat([]byte{0x55}, 100)...)
+	output, err := bind(t, [][]byte{input}, cut(0, 2*d), DetectCorruption(func(int, []Corruption) error {
+		return errTest
+	}))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []int{0, 1}, numbersOf(t, audioFrames(t, output)))
+	}
+}
+
+func TestCutRewindsSharedInput(t *testing.T) {
+	t.Parallel()
+
+	frames := numberedFrames(t, 5)
+	d := duration(frames[0])
+
+	// an input file bound twice shares its reader
+	shared := rewindingreader.New(bytes.NewReader(stream(frames...)))
+	output, err := bindReaders(t, []io.Reader{shared, shared}, Cut(func(index int) (time.Duration, time.Duration) {
+		if index == 0 {
+			return 0, 2 * d
+		}
+
+		return d, 0
+	}))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []int{0, 1, 1, 2, 3, 4}, numbersOf(t, audioFrames(t, output)))
+	}
+}
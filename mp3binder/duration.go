@@ -0,0 +1,107 @@
+package mp3binder
+
+import (
+	"encoding/binary"
+	"strconv"
+	"time"
+
+	"github.com/crra/id3v2/v2"
+	"github.com/dmulholl/mp3lib"
+)
+
+const (
+	tagLength = "TLEN"
+
+	// sources of the expected duration of an input file
+	SourceXing = "Xing header"
+	SourceVBRI = "VBRI header"
+	SourceTLEN = "TLEN tag"
+
+	xingFramesFlag = 0x01
+	vbriOffset     = 4 + 32
+	// offset of the number of frames in the VBRI header (after the id, version, delay, quality and bytes)
+	vbriFramesOffset = vbriOffset + 14
+	// taggers round the length or take it from the header
+	tagLengthTolerance = time.Second
+)
+
+type durationVisitor func(index int, source string, expected, computed time.Duration)
+
+// VerifyDurations compares the duration of each input file computed from its frames with the number
+// of frames of the Xing or VBRI header and the length tag ('TLEN'). The visitor receives the
+// mismatches (e.g. of truncated or corrupt files).
+func VerifyDurations(visitor durationVisitor) Option {
+	return func() (stage, string, jobProcessor) {
+		return stageInit, "verify durations", func(j *job) error {
+			j.durationVisitor = visitor
+
+			return nil
+		}
+	}
+}
+
+// headerFrames returns the number of frames of the stream from a Xing (or Info) or VBRI header.
+func headerFrames(frame *mp3lib.MP3Frame) (uint32, string, bool) {
+	switch {
+	case mp3lib.IsXingHeader(frame):
+		offset := 4 + getSideInfoSize(frame) + 4
+		if len(frame.RawBytes) < offset+8 || binary.BigEndian.Uint32(frame.RawBytes[offset:])&xingFramesFlag == 0 {
+			return 0, SourceXing, false
+		}
+
+		return binary.BigEndian.Uint32(frame.RawBytes[offset+4:]), SourceXing, true
+	case mp3lib.IsVbriHeader(frame):
+		if len(frame.RawBytes) < vbriFramesOffset+4 {
+			return 0, SourceVBRI, false
+		}
+
+		return binary.BigEndian.Uint32(frame.RawBytes[vbriFramesOffset:]), SourceVBRI, true
+	default:
+		return 0, "", false
+	}
+}
+
+// verifyDuration compares the computed duration of the input file at the index with its header frame
+// (if any) and its length tag.
+func (j *job) verifyDuration(index int, header *mp3lib.MP3Frame, computed time.Duration) {
+	if j.durationVisitor == nil {
+		return
+	}
+
+	if header != nil {
+		if frames, source, ok := headerFrames(header); ok {
+			frameDuration := duration(header)
+			expected := time.Duration(frames) * frameDuration
+
+			// some encoders count the header frame
+			if d := expected - computed; d > frameDuration || d < -frameDuration {
+				j.durationVisitor(index, source, expected, computed)
+			}
+		}
+	}
+
+	if tlen, ok := j.metadata[index].GetLastFrame(tagLength).(id3v2.TextFrame); ok {
+		if ms, err := strconv.Atoi(tlen.Text); err == nil && ms > 0 {
+			expected := time.Duration(ms) * time.Millisecond
+
+			if d := expected - computed; d > tagLengthTolerance || d < -tagLengthTolerance {
+				j.durationVisitor(index, SourceTLEN, expected, computed)
+			}
+		}
+	}
+}
+
+// writeLength writes the length of the output file in milliseconds ('TLEN'), a length copied from
+// an input file is replaced.
+func writeLength() (stage, string, jobProcessor) {
+	return stageCopyMetadata, "writing length", func(j *job) error {
+		var length time.Duration
+		for i := range j.inputDurations {
+			length += j.inputDurations[i] + j.gapDurations[i]
+		}
+
+		j.tag.AddTextFrame(tagLength, j.tag.DefaultEncoding(), strconv.FormatInt(length.Milliseconds(), 10))
+
+		return nil
+	}
+}
@@ -0,0 +1,106 @@
+package mp3binder
+
+import (
+	"encoding/binary"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/crra/id3v2/v2"
+	"github.com/dmulholl/mp3lib"
+	"github.com/stretchr/testify/assert"
+)
+
+type durationMismatch struct {
+	source             string
+	expected, computed time.Duration
+}
+
+func TestVerifyDuration(t *testing.T) {
+	t.Parallel()
+
+	frameDuration := duration(makeFrame(t, header44100))
+
+	vbriHeader := func(frames uint32) *mp3lib.MP3Frame {
+		frame := makeFrame(t, header44100)
+		copy(frame.RawBytes[vbriOffset:], "VBRI")
+		binary.BigEndian.PutUint32(frame.RawBytes[vbriFramesOffset:], frames)
+
+		return frame
+	}
+
+	lengthTag := func(d time.Duration) *id3v2.Tag {
+		tag := id3v2.NewEmptyTag()
+		tag.AddTextFrame(tagLength, id3v2.EncodingISO, strconv.FormatInt(d.Milliseconds(), 10))
+
+		return tag
+	}
+
+	computed := 100 * frameDuration
+
+	for _, f := range []struct {
+		name     string
+		header   *mp3lib.MP3Frame
+		tag      *id3v2.Tag
+		expected []durationMismatch
+	}{
+		{name: "no header and tag"},
+		{name: "Xing matches", header: mp3lib.NewXingHeader(100, 0)},
+		{name: "Xing counts itself", header: mp3lib.NewXingHeader(101, 0)},
+		{name: "Xing mismatch", header: mp3lib.NewXingHeader(150, 0), expected: []durationMismatch{{SourceXing, 150 * frameDuration, computed}}},
+		{name: "Xing without frames", header: func() *mp3lib.MP3Frame {
+			header := mp3lib.NewXingHeader(150, 0)
+			header.RawBytes[4+getSideInfoSize(header)+7] = xingBytesFlag
+
+			return header
+		}()},
+		{name: "VBRI matches", header: vbriHeader(100)},
+		{name: "VBRI mismatch", header: vbriHeader(50), expected: []durationMismatch{{SourceVBRI, 50 * frameDuration, computed}}},
+		{name: "TLEN rounded", tag: lengthTag(computed + 500*time.Millisecond)},
+		{name: "TLEN mismatch", tag: lengthTag(computed + 2*time.Second), expected: []durationMismatch{{SourceTLEN, (computed + 2*time.Second).Truncate(time.Millisecond), computed}}},
+		{
+			name: "Xing and TLEN mismatch", header: mp3lib.NewXingHeader(150, 0), tag: lengthTag(time.Second),
+			expected: []durationMismatch{{SourceXing, 150 * frameDuration, computed}, {SourceTLEN, time.Second, computed}},
+		},
+	} {
+		f := f // pin
+		t.Run(f.name, func(t *testing.T) {
+			t.Parallel()
+
+			tag := f.tag
+			if tag == nil {
+				tag = id3v2.NewEmptyTag()
+			}
+
+			var mismatches []durationMismatch
+			j := &job{
+				metadata: []*id3v2.Tag{nil, tag},
+				durationVisitor: func(index int, source string, expected, computed time.Duration) {
+					assert.Equal(t, 1, index)
+					mismatches = append(mismatches, durationMismatch{source, expected, computed})
+				},
+			}
+
+			j.verifyDuration(1, f.header, computed)
+			assert.Equal(t, f.expected, mismatches)
+		})
+	}
+}
+
+func TestVerifyDurations(t *testing.T) {
+	t.Parallel()
+
+	frame := makeFrame(t, header44100)
+	truncated := append(mp3lib.NewXingHeader(10, 0).RawBytes, stream(repeat(frame, 4)...)...)
+	complete := append(mp3lib.NewXingHeader(4, 0).RawBytes, stream(repeat(frame, 4)...)...)
+
+	var mismatches []durationMismatch
+	_, err := bind(t, [][]byte{complete, truncated}, VerifyDurations(func(index int, source string, expected, computed time.Duration) {
+		assert.Equal(t, 1, index)
+		mismatches = append(mismatches, durationMismatch{source, expected, computed})
+	}))
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, []durationMismatch{{SourceXing, 10 * duration(frame), 4 * duration(frame)}}, mismatches)
+	}
+}
@@ -0,0 +1,247 @@
+package mp3binder
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+
+	"github.com/crra/id3v2/v2"
+	"github.com/crra/mp3binder/mp3binder/tags"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+const defaultLanguage = "eng"
+
+// frameFor creates the frame for a key and its value (e.g. a comment for 'COMM:eng:description').
+func frameFor(key tags.Key, value string, enc id3v2.Encoding) (id3v2.Framer, error) {
+	key = withDefaultLanguage(key)
+
+	switch {
+	case key.ID == tags.IdUserDefinedText:
+		return id3v2.UserDefinedTextFrame{Encoding: enc, Description: key.Description, Value: value}, nil
+	case key.ID == tags.IdComment:
+		return id3v2.CommentFrame{Encoding: enc, Language: key.Language, Description: key.Description, Text: value}, nil
+	case key.ID == tags.IdLyrics:
+		return id3v2.UnsynchronisedLyricsFrame{Encoding: enc, Language: key.Language, ContentDescriptor: key.Description, Lyrics: value}, nil
+	case key.ID == tags.IdPopularimeter:
+		if value == "" {
+			return id3v2.PopularimeterFrame{Email: key.Description}, nil
+		}
+
+		rating, counter, err := tags.ParsePopularimeter(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return id3v2.PopularimeterFrame{Email: key.Description, Rating: rating, Counter: counter}, nil
+	case key.ID == tags.IdUserDefinedURL:
+		return userDefinedURLFrame{Encoding: enc, Description: key.Description, URL: value}, nil
+	case key.IsURL():
+		return urlFrame{URL: value}, nil
+	default:
+		return id3v2.TextFrame{Encoding: enc, Text: value}, nil
+	}
+}
+
+// keyOf returns the key of a frame and its value, if the frame holds text.
+func keyOf(id string, f id3v2.Framer) (tags.Key, string, bool) {
+	switch ff := f.(type) {
+	case id3v2.TextFrame:
+		return tags.Key{ID: id}, ff.Text, true
+	case id3v2.UserDefinedTextFrame:
+		return tags.Key{ID: id, Description: ff.Description}, ff.Value, true
+	case id3v2.CommentFrame:
+		return tags.Key{ID: id, Language: ff.Language, Description: ff.Description}, ff.Text, true
+	case id3v2.UnsynchronisedLyricsFrame:
+		return tags.Key{ID: id, Language: ff.Language, Description: ff.ContentDescriptor}, ff.Lyrics, true
+	case id3v2.PopularimeterFrame:
+		counter := "0"
+		if ff.Counter != nil {
+			counter = ff.Counter.String()
+		}
+
+		return tags.Key{ID: id, Description: ff.Email}, strconv.Itoa(int(ff.Rating)) + "/" + counter, true
+	case userDefinedURLFrame:
+		return tags.Key{ID: id, Description: ff.Description}, ff.URL, true
+	case urlFrame:
+		return tags.Key{ID: id}, ff.URL, true
+	case id3v2.UnknownFrame:
+		// the id3v2 library does not parse url frames
+		key := tags.Key{ID: id}
+		if !key.IsURL() {
+			return tags.Key{}, "", false
+		}
+
+		if id == tags.IdUserDefinedURL {
+			uf, ok := parseUserDefinedURLFrame(ff.Body)
+			if !ok {
+				return tags.Key{}, "", false
+			}
+
+			return tags.Key{ID: id, Description: uf.Description}, uf.URL, true
+		}
+
+		return key, string(bytes.TrimRight(ff.Body, "\x00")), true
+	default:
+		return tags.Key{}, "", false
+	}
+}
+
+// deleteFrame removes the frame of a key. A key without qualifiers removes all frames with the id.
+func deleteFrame(tag *id3v2.Tag, key tags.Key) {
+	if !key.HasQualifiers() {
+		tag.DeleteFrames(key.ID)
+		return
+	}
+
+	key = withDefaultLanguage(key)
+	frames := tag.GetFrames(key.ID)
+	tag.DeleteFrames(key.ID)
+
+	for _, f := range frames {
+		if k, _, ok := keyOf(key.ID, f); ok && withDefaultLanguage(k) == key {
+			continue
+		}
+
+		tag.AddFrame(key.ID, f)
+	}
+}
+
+func withDefaultLanguage(key tags.Key) tags.Key {
+	if key.IsLongText() && key.Language == "" {
+		key.Language = defaultLanguage
+	}
+
+	return key
+}
+
+// urlFrame is a frame holding a single url (e.g. 'WOAR'), which the id3v2 library does not provide.
+type urlFrame struct {
+	URL string
+}
+
+// Size implements the id3v2.Framer interface.
+func (uf urlFrame) Size() int {
+	return len(latin1(uf.URL))
+}
+
+// UniqueIdentifier implements the id3v2.Framer interface.
+func (uf urlFrame) UniqueIdentifier() string {
+	return ""
+}
+
+// WriteTo implements the id3v2.Framer interface.
+func (uf urlFrame) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(latin1(uf.URL))
+	return int64(n), err
+}
+
+// userDefinedURLFrame is a link with a description ('WXXX'), which the id3v2 library does not provide.
+type userDefinedURLFrame struct {
+	Encoding    id3v2.Encoding
+	Description string
+	URL         string
+}
+
+// Size implements the id3v2.Framer interface.
+func (uf userDefinedURLFrame) Size() int {
+	return len(uf.body())
+}
+
+// UniqueIdentifier implements the id3v2.Framer interface.
+func (uf userDefinedURLFrame) UniqueIdentifier() string {
+	return uf.Description
+}
+
+// WriteTo implements the id3v2.Framer interface.
+func (uf userDefinedURLFrame) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(uf.body())
+	return int64(n), err
+}
+
+func (uf userDefinedURLFrame) body() []byte {
+	description, err := textEncoding(uf.Encoding).NewEncoder().String(uf.Description)
+	if err != nil {
+		description = uf.Description
+	}
+
+	b := make([]byte, 0, 1+len(description)+len(uf.Encoding.TerminationBytes)+len(uf.URL))
+	b = append(b, uf.Encoding.Key)
+	b = append(b, description...)
+	b = append(b, uf.Encoding.TerminationBytes...)
+
+	return append(b, latin1(uf.URL)...)
+}
+
+// parseUserDefinedURLFrame parses the body of a 'WXXX' frame.
+func parseUserDefinedURLFrame(body []byte) (userDefinedURLFrame, bool) {
+	if len(body) < 1 {
+		return userDefinedURLFrame{}, false
+	}
+
+	var enc id3v2.Encoding
+	switch body[0] {
+	case id3v2.EncodingISO.Key:
+		enc = id3v2.EncodingISO
+	case id3v2.EncodingUTF16.Key:
+		enc = id3v2.EncodingUTF16
+	case id3v2.EncodingUTF16BE.Key:
+		enc = id3v2.EncodingUTF16BE
+	default:
+		enc = id3v2.EncodingUTF8
+	}
+
+	rest := body[1:]
+	end := terminatorIndex(rest, enc.TerminationBytes)
+	if end < 0 {
+		return userDefinedURLFrame{}, false
+	}
+
+	description, err := textEncoding(enc).NewDecoder().Bytes(rest[:end])
+	if err != nil {
+		return userDefinedURLFrame{}, false
+	}
+
+	url, err := charmap.ISO8859_1.NewDecoder().Bytes(bytes.TrimRight(rest[end+len(enc.TerminationBytes):], "\x00"))
+	if err != nil {
+		return userDefinedURLFrame{}, false
+	}
+
+	return userDefinedURLFrame{Encoding: enc, Description: string(description), URL: string(url)}, true
+}
+
+// terminatorIndex finds the terminator of a text, which is aligned for two byte encodings.
+func terminatorIndex(b, terminator []byte) int {
+	for i := 0; i+len(terminator) <= len(b); i += len(terminator) {
+		if bytes.Equal(b[i:i+len(terminator)], terminator) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func textEncoding(enc id3v2.Encoding) encoding.Encoding {
+	switch enc.Key {
+	case id3v2.EncodingISO.Key:
+		return charmap.ISO8859_1
+	case id3v2.EncodingUTF16.Key:
+		return unicode.UTF16(unicode.LittleEndian, unicode.UseBOM)
+	case id3v2.EncodingUTF16BE.Key:
+		return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM)
+	default:
+		return encoding.Nop
+	}
+}
+
+// latin1 encodes a text as ISO-8859-1, which is mandatory for urls.
+func latin1(s string) []byte {
+	b, err := charmap.ISO8859_1.NewEncoder().Bytes([]byte(s))
+	if err != nil {
+		return []byte(s)
+	}
+
+	return b
+}
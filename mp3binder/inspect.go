@@ -0,0 +1,227 @@
+package mp3binder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/crra/id3v2/v2"
+	"github.com/dmulholl/mp3lib"
+)
+
+var ErrNoAudio = errors.New("no audio frames")
+
+const (
+	tagChapter = "CHAP"
+
+	xingBytesFlag   = 0x02
+	xingTOCFlag     = 0x04
+	xingQualityFlag = 0x08
+	xingTOCSize     = 100
+	// the LAME extension follows the Xing header
+	lameEncoderSize = 9
+	// offset of the encoder delay and padding (12 bits each) from the start of the LAME extension
+	lameDelayOffset = 21
+	// offset of the number of bytes in the VBRI header (after the id, version, delay and quality)
+	vbriBytesOffset = vbriOffset + 10
+)
+
+// Inspection describes the audio frames, the bitrate header and the id3v2 tags of a mp3 file.
+type Inspection struct {
+	Stream StreamInfo
+	Frames int
+	// AverageBitRate of the audio frames in bits per second
+	AverageBitRate int
+	// Header is the Xing, Info or VBRI header, if any
+	Header *BitrateHeader
+	// Tags are the text frames by the canonical key of tags.Key: the id, followed by the language
+	// and the description separated by colons for frames that can be present multiple times
+	// (e.g. 'TIT2', 'TXXX:ASIN' or 'COMM:eng:description')
+	Tags     map[string]string
+	Pictures []PictureInfo
+	Chapters []ChapterInfo
+}
+
+// BitrateHeader is the content of the first frame of a mp3 file that describes the stream
+// (e.g. for seeking in files with a variable bitrate).
+type BitrateHeader struct {
+	// Kind is 'Xing', 'Info' or 'VBRI'
+	Kind string
+	// Frames and Bytes are zero if not present
+	Frames  uint32
+	Bytes   uint32
+	Quality int
+	// Encoder of the LAME extension (e.g. 'LAME3.100'), empty if not present
+	Encoder string
+	// EncoderDelay and EncoderPadding in samples of the LAME extension
+	EncoderDelay   int
+	EncoderPadding int
+}
+
+// PictureInfo describes an attached picture (e.g. the front cover).
+type PictureInfo struct {
+	PictureType byte
+	Description string
+	MimeType    string
+	Size        int
+}
+
+// ChapterInfo describes a chapter.
+type ChapterInfo struct {
+	ID    string
+	Start time.Duration
+	End   time.Duration
+	Title string
+}
+
+// Inspect reads the format and the duration of the audio frames, the bitrate header and the
+// id3v2 tags (e.g. the pictures and the chapters) of a mp3 file.
+func Inspect(r io.Reader) (Inspection, error) {
+	var inspection Inspection
+
+	s := &streamScanner{}
+	tag := id3v2.NewEmptyTag()
+	firstFrame := true
+
+	for {
+		obj := mp3lib.NextObject(r)
+		if obj == nil {
+			break
+		}
+
+		switch obj := obj.(type) {
+		case *mp3lib.MP3Frame:
+			if firstFrame && (mp3lib.IsXingHeader(obj) || mp3lib.IsVbriHeader(obj)) {
+				firstFrame = false
+				inspection.Header = readBitrateHeader(obj)
+				continue
+			}
+			firstFrame = false
+
+			s.add(obj)
+
+		case *mp3lib.ID3v2Tag:
+			t, err := id3v2.ParseReader(bytes.NewReader(obj.RawBytes), id3v2.Options{Parse: true})
+			if err != nil {
+				return inspection, err
+			}
+
+			for id, frames := range t.AllFrames() {
+				for _, f := range frames {
+					tag.AddFrame(id, f)
+				}
+			}
+		}
+	}
+
+	stream, ok := s.info()
+	if !ok {
+		return inspection, ErrNoAudio
+	}
+
+	inspection.Stream = stream
+	inspection.Frames = s.frames
+	if seconds := stream.Duration.Seconds(); seconds > 0 {
+		inspection.AverageBitRate = int(float64(s.bytes*8) / seconds)
+	}
+
+	inspection.Tags = tagToMap(tag)
+
+	for _, f := range tag.GetFrames(tagPicture) {
+		if pf, ok := f.(id3v2.PictureFrame); ok {
+			inspection.Pictures = append(inspection.Pictures, PictureInfo{
+				PictureType: pf.PictureType,
+				Description: pf.Description,
+				MimeType:    pf.MimeType,
+				Size:        len(pf.Picture),
+			})
+		}
+	}
+
+	for _, f := range tag.GetFrames(tagChapter) {
+		if cf, ok := f.(id3v2.ChapterFrame); ok {
+			chapter := ChapterInfo{ID: cf.ElementID, Start: cf.StartTime, End: cf.EndTime}
+			if cf.Title != nil {
+				chapter.Title = cf.Title.Text
+			}
+
+			inspection.Chapters = append(inspection.Chapters, chapter)
+		}
+	}
+
+	sort.SliceStable(inspection.Chapters, func(i, j int) bool {
+		return inspection.Chapters[i].Start < inspection.Chapters[j].Start
+	})
+
+	return inspection, nil
+}
+
+// readBitrateHeader reads the Xing (or Info) header with its LAME extension or the VBRI header.
+func readBitrateHeader(frame *mp3lib.MP3Frame) *BitrateHeader {
+	data := frame.RawBytes
+
+	if mp3lib.IsVbriHeader(frame) {
+		header := &BitrateHeader{Kind: "VBRI"}
+		if len(data) >= vbriFramesOffset+4 {
+			header.Quality = int(binary.BigEndian.Uint16(data[vbriOffset+8:]))
+			header.Bytes = binary.BigEndian.Uint32(data[vbriBytesOffset:])
+			header.Frames = binary.BigEndian.Uint32(data[vbriFramesOffset:])
+		}
+
+		return header
+	}
+
+	offset := 4 + getSideInfoSize(frame)
+	header := &BitrateHeader{Kind: string(data[offset : offset+4])}
+
+	offset += 4
+	if len(data) < offset+4 {
+		return header
+	}
+
+	flags := binary.BigEndian.Uint32(data[offset:])
+	offset += 4
+
+	// the fields are present in the order of the flags
+	for _, field := range []struct {
+		flag uint32
+		size int
+		read func([]byte)
+	}{
+		{flag: xingFramesFlag, size: 4, read: func(b []byte) { header.Frames = binary.BigEndian.Uint32(b) }},
+		{flag: xingBytesFlag, size: 4, read: func(b []byte) { header.Bytes = binary.BigEndian.Uint32(b) }},
+		{flag: xingTOCFlag, size: xingTOCSize, read: func([]byte) {}},
+		{flag: xingQualityFlag, size: 4, read: func(b []byte) { header.Quality = int(binary.BigEndian.Uint32(b)) }},
+	} {
+		if flags&field.flag == 0 {
+			continue
+		}
+
+		if len(data) < offset+field.size {
+			return header
+		}
+
+		field.read(data[offset:])
+		offset += field.size
+	}
+
+	if len(data) < offset+lameDelayOffset+3 {
+		return header
+	}
+
+	encoder := strings.TrimRight(string(data[offset:offset+lameEncoderSize]), "\x00 ")
+	if !strings.HasPrefix(encoder, "LAME") && !strings.HasPrefix(encoder, "Lavc") && !strings.HasPrefix(encoder, "Lavf") {
+		return header
+	}
+
+	header.Encoder = encoder
+	delay := data[offset+lameDelayOffset:]
+	header.EncoderDelay = int(delay[0])<<4 | int(delay[1])>>4
+	header.EncoderPadding = int(delay[1]&0x0F)<<8 | int(delay[2])
+
+	return header
+}
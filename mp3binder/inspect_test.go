@@ -0,0 +1,33 @@
+package mp3binder
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/crra/id3v2/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInspectTagKeys(t *testing.T) {
+	t.Parallel()
+
+	tag := id3v2.NewEmptyTag()
+	tag.SetVersion(4)
+	tag.AddTextFrame("TIT2", id3v2.EncodingUTF8, "Title")
+	tag.AddUserDefinedTextFrame(id3v2.UserDefinedTextFrame{Encoding: id3v2.EncodingUTF8, Description: "ASIN", Value: "B000"})
+	tag.AddCommentFrame(id3v2.CommentFrame{Encoding: id3v2.EncodingUTF8, Language: "eng", Description: "Notes", Text: "Some notes"})
+
+	var b bytes.Buffer
+	if _, err := tag.WriteTo(&b); err != nil {
+		panic(err)
+	}
+
+	inspection, err := Inspect(bytes.NewReader(append(b.Bytes(), stream(repeat(makeFrame(t, header44100), 2)...)...)))
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]string{
+			"TIT2":           "Title",
+			"TXXX:ASIN":      "B000",
+			"COMM:eng:Notes": "Some notes",
+		}, inspection.Tags)
+	}
+}
@@ -0,0 +1,70 @@
+package mp3binder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MergeStrategy defines how the values of a tag from multiple input files are combined.
+type MergeStrategy int
+
+const (
+	// MergeFirst takes the first non-empty value.
+	MergeFirst MergeStrategy = iota
+	// MergeMostCommon takes the value that occurs most often.
+	MergeMostCommon
+	// MergeConcat concatenates all distinct values.
+	MergeConcat
+)
+
+func (s MergeStrategy) String() string {
+	switch s {
+	case MergeFirst:
+		return "first"
+	case MergeMostCommon:
+		return "most common"
+	case MergeConcat:
+		return "concatenated"
+	default:
+		return fmt.Sprintf("MergeStrategy(%d)", int(s))
+	}
+}
+
+// mergeValues combines the values according to the strategy. Empty values are ignored.
+func mergeValues(strategy MergeStrategy, values []string, separator string) string {
+	var distinct []string
+	counts := make(map[string]int)
+
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+
+		if _, seen := counts[v]; !seen {
+			distinct = append(distinct, v)
+		}
+
+		counts[v]++
+	}
+
+	if len(distinct) == 0 {
+		return ""
+	}
+
+	switch strategy {
+	case MergeMostCommon:
+		// stable: on a tie, the value that occurred first wins
+		sort.SliceStable(distinct, func(p, q int) bool {
+			return counts[distinct[p]] > counts[distinct[q]]
+		})
+
+		return distinct[0]
+	case MergeConcat:
+		return strings.Join(distinct, separator)
+	case MergeFirst:
+		fallthrough
+	default:
+		return distinct[0]
+	}
+}
@@ -0,0 +1,82 @@
+package mp3binder
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/crra/id3v2/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// withTextFrames prepends a tag with the text frames to a stream.
+func withTextFrames(texts map[string]string, audio []byte) []byte {
+	tag := id3v2.NewEmptyTag()
+	tag.SetVersion(4)
+	for id, text := range texts {
+		tag.AddTextFrame(id, id3v2.EncodingUTF8, text)
+	}
+
+	var b bytes.Buffer
+	if _, err := tag.WriteTo(&b); err != nil {
+		panic(err)
+	}
+
+	return append(b.Bytes(), audio...)
+}
+
+func TestMergeValues(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []struct {
+		name     string
+		strategy MergeStrategy
+		values   []string
+		expected string
+	}{
+		{name: "first", strategy: MergeFirst, values: []string{"a", "b", "b"}, expected: "a"},
+		{name: "first non-empty", strategy: MergeFirst, values: []string{"", "b", "a"}, expected: "b"},
+		{name: "common", strategy: MergeMostCommon, values: []string{"a", "b", "b", "c"}, expected: "b"},
+		{name: "common on a tie", strategy: MergeMostCommon, values: []string{"a", "b", "b", "a"}, expected: "a"},
+		{name: "common without empty values", strategy: MergeMostCommon, values: []string{"", "", "a"}, expected: "a"},
+		{name: "concat", strategy: MergeConcat, values: []string{"a", "b", "c"}, expected: "a\x00b\x00c"},
+		{name: "concat distinct values", strategy: MergeConcat, values: []string{"a", "b", "", "a"}, expected: "a\x00b"},
+		{name: "no values", strategy: MergeConcat, values: nil, expected: ""},
+		{name: "only empty values", strategy: MergeFirst, values: []string{"", ""}, expected: ""},
+	} {
+		f := f // pin
+		t.Run(f.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, f.expected, mergeValues(f.strategy, f.values, "\x00"))
+		})
+	}
+}
+
+func TestMergeMetadata(t *testing.T) {
+	t.Parallel()
+
+	audio := stream(repeat(makeFrame(t, header44100), 2)...)
+	inputs := [][]byte{
+		withTextFrames(map[string]string{"TALB": "Album", "TCOM": "Bach", "TPE1": "First"}, audio),
+		withTextFrames(map[string]string{"TALB": "Other", "TCOM": "Händel", "TPE1": "Second"}, audio),
+		// a spacer (e.g. an interlace file) is not merged
+		withTextFrames(map[string]string{"TALB": "Other", "TCOM": "Spacer", "TPE1": "Spacer"}, audio),
+		withTextFrames(map[string]string{"TALB": "Album", "TCOM": "Bach"}, audio),
+	}
+
+	strategies := map[string]MergeStrategy{"TALB": MergeMostCommon, "TCOM": MergeConcat, "TPE1": MergeFirst}
+	output, err := bind(t, inputs, MergeMetadata(func(id string) (MergeStrategy, bool) {
+		strategy, ok := strategies[id]
+		return strategy, ok
+	}, "\x00", func(index int) bool { return index != 2 }))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	tag, err := id3v2.ParseReader(bytes.NewReader(output), id3v2.Options{Parse: true})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Album", tag.GetTextFrame("TALB").Text)
+		assert.Equal(t, "Bach\x00Händel", tag.GetTextFrame("TCOM").Text)
+		assert.Equal(t, "First", tag.GetTextFrame("TPE1").Text)
+	}
+}
@@ -30,6 +30,15 @@ type job struct {
 	metadata    []*id3v2.Tag
 
 	inputDurations  []time.Duration
+	gapAfter        func(int) time.Duration
+	gapDurations    []time.Duration
+	trim            *trim
+	rangeOf         func(int) timeRange
+	normalize       *normalize
+	crc             *crcCheck
+	durationVisitor durationVisitor
+	corruptVisitor  corruptVisitor
+	chapterArtwork  bool
 	stageVisitor    stageVisitor
 	metadataVisitor metadataVisitor
 	bindVisitor     bindVisitor
@@ -90,8 +99,12 @@ func Bind(parent context.Context, tagResolver tagResolver, output io.WriteSeeker
 
 		tag:            id3v2.NewEmptyTag(),
 		inputDurations: make([]time.Duration, len(input)),
+		gapDurations:   make([]time.Duration, len(input)),
 		metadata:       make([]*id3v2.Tag, len(input)),
 
+		gapAfter: func(int) time.Duration { return 0 },
+		rangeOf:  func(int) timeRange { return timeRange{} },
+
 		stageVisitor:    func(string, string) {},
 		metadataVisitor: func(int, map[string]string) {},
 		bindVisitor:     func(int) {},
@@ -101,7 +114,7 @@ func Bind(parent context.Context, tagResolver tagResolver, output io.WriteSeeker
 
 	jobProcessors := make(map[stage][]namedJobProcessor)
 
-	options = append(options, bindAudioOnly, notifyMetadata, writeMetadata, combineMetadataAndAudio)
+	options = append(options, bindAudioOnly, writeLength, writeReplayGain, notifyMetadata, writeMetadata, combineMetadataAndAudio)
 
 	for _, o := range options {
 		stage, name, processor := o()
@@ -146,6 +159,45 @@ func bindAudioOnly() (stage, string, jobProcessor) {
 		var framesCount uint32
 		var lastBitrate int
 		var multipleBitrates bool
+		// the last frame written, the template for the silence of a gap
+		var lastFrame *mp3lib.MP3Frame
+
+		// the analyses read the inputs to their end, they rewind for the binding
+		if j.trim != nil {
+			j.trim.analyze(j.inputs)
+		}
+
+		if j.normalize != nil {
+			j.normalize.analyze(j.inputs)
+		}
+
+		writeFrame := func(fileIndex int, frame *mp3lib.MP3Frame) error {
+			if j.normalize != nil {
+				adjustGlobalGain(frame, j.normalize.steps[fileIndex])
+			}
+
+			if lastBitrate == 0 {
+				lastBitrate = frame.BitRate
+			}
+
+			if !multipleBitrates && lastBitrate != frame.BitRate {
+				multipleBitrates = true
+			}
+
+			if _, err := j.audioOnly.Write(frame.RawBytes); err != nil {
+				return err
+			}
+
+			j.inputDurations[fileIndex] += duration(frame)
+
+			framesCount++
+
+			bytesCount += uint32(len(frame.RawBytes))
+
+			lastFrame = frame
+
+			return nil
+		}
 
 		for fileIndex, reader := range j.inputs {
 			j.bindVisitor(fileIndex)
@@ -154,40 +206,88 @@ func bindAudioOnly() (stage, string, jobProcessor) {
 				j.metadata[fileIndex] = id3v2.NewEmptyTag()
 			}
 
+			cut := j.rangeOf(fileIndex)
+			// the position of the frame in the input file
+			var position time.Duration
+			// the Xing or VBRI header of the input file
+			var header *mp3lib.MP3Frame
+			// the header can only be the first frame, tags may precede it
+			firstFrame := true
+			// the regions skipped by the parser (e.g. garbage)
+			detector := &corruptionDetector{r: reader}
+			// the positions of the frames with a wrong CRC
+			var crcMismatches []time.Duration
+
+			trimming := j.trim != nil && j.trim.include(fileIndex)
+			// the silent frames are written once an audible frame follows
+			var audible bool
+			var leading time.Duration
+			var silent []*mp3lib.MP3Frame
+			// the rest of the input file after the range is not read
+			var ended bool
+
 		Loop:
-			for i := 0; true; i++ {
+			for {
 				select {
 				case <-j.context.Done():
 					return j.context.Err()
 				default:
-					obj := mp3lib.NextObject(reader)
+					obj := detector.next(position)
 					if obj == nil {
 						break Loop
 					}
 
 					switch obj := obj.(type) {
 					case *mp3lib.MP3Frame:
-						if i == 0 && (mp3lib.IsXingHeader(obj) || mp3lib.IsVbriHeader(obj)) {
+						if firstFrame && (mp3lib.IsXingHeader(obj) || mp3lib.IsVbriHeader(obj)) {
+							firstFrame = false
+							header = obj
 							continue
 						}
+						firstFrame = false
 
-						if lastBitrate == 0 {
-							lastBitrate = obj.BitRate
+						start := position
+						if cut.ended(start) {
+							ended = true
+							break Loop
 						}
 
-						if !multipleBitrates && lastBitrate != obj.BitRate {
-							multipleBitrates = true
+						position += duration(obj)
+						if !cut.includes(start, duration(obj)) {
+							continue
 						}
 
-						if _, err := j.audioOnly.Write(obj.RawBytes); err != nil {
-							return err
+						if j.crc != nil && !hasValidCRC(obj) {
+							crcMismatches = append(crcMismatches, start)
+							if j.crc.drop {
+								continue
+							}
 						}
 
-						j.inputDurations[fileIndex] += duration(obj)
-
-						framesCount++
+						if trimming {
+							if isSilent(obj, j.trim.maxGlobalGains[fileIndex]) {
+								if !audible {
+									leading += duration(obj)
+								} else {
+									// trailing, unless audible frames follow
+									silent = append(silent, obj)
+								}
+
+								continue
+							}
+
+							audible = true
+							for _, f := range silent {
+								if err := writeFrame(fileIndex, f); err != nil {
+									return err
+								}
+							}
+							silent = silent[:0]
+						}
 
-						bytesCount += uint32(len(obj.RawBytes))
+						if err := writeFrame(fileIndex, obj); err != nil {
+							return err
+						}
 
 					case *mp3lib.ID3v2Tag:
 						tag, err := id3v2.ParseReader(bytes.NewReader(obj.RawBytes), id3v2.Options{Parse: true})
@@ -195,8 +295,10 @@ func bindAudioOnly() (stage, string, jobProcessor) {
 							return err
 						}
 
-						for id := range tag.AllFrames() {
-							j.metadata[fileIndex].AddFrame(id, tag.GetLastFrame(id))
+						for id, frames := range tag.AllFrames() {
+							for _, f := range frames {
+								j.metadata[fileIndex].AddFrame(id, f)
+							}
 						}
 
 					default:
@@ -204,6 +306,51 @@ func bindAudioOnly() (stage, string, jobProcessor) {
 					}
 				}
 			}
+
+			if ended {
+				// the duration of the input file is unknown
+				if r, ok := reader.(rewinder); ok {
+					r.Rewind()
+				}
+			} else {
+				j.verifyDuration(fileIndex, header, position)
+			}
+
+			if j.crc != nil && len(crcMismatches) > 0 {
+				j.crc.visitor(fileIndex, crcMismatches)
+			}
+
+			if j.corruptVisitor != nil && len(detector.corruptions) > 0 {
+				if err := j.corruptVisitor(fileIndex, detector.corruptions); err != nil {
+					return err
+				}
+			}
+
+			if trimming {
+				var trailing time.Duration
+				for _, f := range silent {
+					trailing += duration(f)
+				}
+
+				j.trim.visitor(fileIndex, leading, trailing)
+			}
+
+			if fileIndex == len(j.inputs)-1 || lastFrame == nil {
+				continue
+			}
+
+			if n := silentFrames(lastFrame, j.gapAfter(fileIndex)); n > 0 {
+				silence := silentFrame(lastFrame)
+				for i := 0; i < n; i++ {
+					if _, err := j.audioOnly.Write(silence); err != nil {
+						return err
+					}
+				}
+
+				j.gapDurations[fileIndex] = time.Duration(n) * duration(lastFrame)
+				framesCount += uint32(n)
+				bytesCount += uint32(n * len(silence))
+			}
 		}
 
 		if err := writeBitrateHeader(j.audioOnly, framesCount, bytesCount, multipleBitrates); err != nil {
@@ -220,10 +367,11 @@ func tagToMap(tag *id3v2.Tag) map[string]string {
 		return m
 	}
 
-	for id := range tag.AllFrames() {
-		f := tag.GetLastFrame(id)
-		if tf, ok := f.(id3v2.TextFrame); ok {
-			m[id] = tf.Text
+	for id, frames := range tag.AllFrames() {
+		for _, f := range frames {
+			if key, value, ok := keyOf(id, f); ok {
+				m[key.String()] = value
+			}
 		}
 	}
 
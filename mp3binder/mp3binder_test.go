@@ -0,0 +1,140 @@
+package mp3binder
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/crra/mp3binder/io/rewindingreader"
+	"github.com/crra/mp3binder/mp3binder/tags"
+	"github.com/dmulholl/mp3lib"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	// MPEG-1 Layer III, 128 kbps, 44100 Hz, stereo, 417 bytes
+	header44100 = []byte{0xFF, 0xFB, 0x90, 0x00}
+	// as above, protected by a CRC
+	header44100CRC = []byte{0xFF, 0xFA, 0x90, 0x00}
+	// MPEG-1 Layer III, 128 kbps, 48000 Hz, stereo, with padding, protected by a CRC, 385 bytes
+	header48000CRCPadding = []byte{0xFF, 0xFA, 0x96, 0x00}
+	// MPEG-2 Layer III, 64 kbps, 22050 Hz, mono, 208 bytes
+	header22050Mono = []byte{0xFF, 0xF3, 0x80, 0xC0}
+
+	errTest = errors.New("test")
+)
+
+// makeFrame returns a frame of the header with zeroed side information and main data.
+func makeFrame(t *testing.T, header []byte) *mp3lib.MP3Frame {
+	t.Helper()
+
+	frame := mp3lib.NextFrame(bytes.NewReader(append(append([]byte{}, header...), make([]byte, 2048)...)))
+	if frame == nil {
+		panic("invalid frame header")
+	}
+
+	updateCRC(frame)
+
+	return frame
+}
+
+// makeAudibleFrame returns a frame of the header whose granules have the audio data, the number
+// of big values and the global gain.
+func makeAudibleFrame(t *testing.T, header []byte, part23Length, bigValues, globalGain int) *mp3lib.MP3Frame {
+	t.Helper()
+
+	frame := makeFrame(t, header)
+	granules, ok := sideInfoGranules(frame)
+	if !ok {
+		panic("invalid side information")
+	}
+
+	// part2_3_length (12 bits) and big_values (9 bits) precede the global gain
+	w := &bitReader{data: frame.RawBytes}
+	for _, g := range granules {
+		w.write(g.globalGainPos-9-12, 12, part23Length)
+		w.write(g.globalGainPos-9, 9, bigValues)
+		w.write(g.globalGainPos, globalGainBits, globalGain)
+	}
+
+	updateCRC(frame)
+
+	return frame
+}
+
+// stream concatenates the frames.
+func stream(frames ...*mp3lib.MP3Frame) []byte {
+	var b []byte
+	for _, f := range frames {
+		b = append(b, f.RawBytes...)
+	}
+
+	return b
+}
+
+// repeat returns the frame count times.
+func repeat(frame *mp3lib.MP3Frame, count int) []*mp3lib.MP3Frame {
+	frames := make([]*mp3lib.MP3Frame, count)
+	for i := range frames {
+		frames[i] = frame
+	}
+
+	return frames
+}
+
+// bind binds the input streams and returns the output file. The inputs rewind like the input files.
+func bind(t *testing.T, inputs [][]byte, options ...Option) ([]byte, error) {
+	t.Helper()
+
+	readers := make([]io.Reader, len(inputs))
+	for i, in := range inputs {
+		readers[i] = rewindingreader.New(bytes.NewReader(in))
+	}
+
+	return bindReaders(t, readers, options...)
+}
+
+// bindReaders binds the readers and returns the output file.
+func bindReaders(t *testing.T, readers []io.Reader, options ...Option) ([]byte, error) {
+	t.Helper()
+
+	fs := afero.NewMemMapFs()
+	output, err := fs.Create("output.mp3")
+	if err != nil {
+		panic(err)
+	}
+	audioOnly, err := fs.Create("audio.mp3")
+	if err != nil {
+		panic(err)
+	}
+
+	if err := Bind(context.Background(), tags.NewV24(errTest, errTest, errTest), output, audioOnly, readers, options...); err != nil {
+		return nil, err
+	}
+
+	return afero.ReadFile(fs, "output.mp3")
+}
+
+// audioFrames returns the audio frames of a bound file without its Xing or Info header.
+func audioFrames(t *testing.T, file []byte) []*mp3lib.MP3Frame {
+	t.Helper()
+
+	r := bytes.NewReader(file)
+	header := mp3lib.NextFrame(r)
+	if !assert.NotNil(t, header) || !assert.True(t, mp3lib.IsXingHeader(header)) {
+		return nil
+	}
+
+	var frames []*mp3lib.MP3Frame
+	for {
+		frame := mp3lib.NextFrame(r)
+		if frame == nil {
+			return frames
+		}
+
+		frames = append(frames, frame)
+	}
+}
@@ -0,0 +1,195 @@
+package mp3binder
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/crra/mp3binder/mp3binder/tags"
+	"github.com/dmulholl/mp3lib"
+)
+
+const (
+	// a step of the global gain changes the volume by 1.5 dB
+	decibelsPerGlobalGainStep = 1 / globalGainStepsPerDecibel
+	maxGlobalGain             = 1<<globalGainBits - 1
+	// average global gain of a stream at the ReplayGain reference loudness (89 dB SPL), an
+	// estimate for common encoders
+	referenceGlobalGain = 170
+	// global gain of the loudest granules of a stream peaking at full scale, an estimate for
+	// common encoders
+	fullScaleGlobalGain = 180
+
+	replayGainTrackGain = "REPLAYGAIN_TRACK_GAIN"
+	replayGainTrackPeak = "REPLAYGAIN_TRACK_PEAK"
+)
+
+type (
+	normalizeVisitor func(index int, adjustment float64)
+
+	// normalize adjusts the volume of the included input files to their average loudness
+	normalize struct {
+		include func(index int) bool
+		visitor normalizeVisitor
+		// the change of the global gain for each input file
+		steps []int
+		// the average and the maximum global gain of the output file
+		level float64
+		peak  int
+	}
+)
+
+// Normalize adjusts the volume of the included input files (e.g. not the interlace files) to their
+// average loudness without re-encoding by changing the global gain of the frames in steps of 1.5 dB.
+// The loudness is estimated from the global gain of the frames. The visitor receives the adjustment
+// in dB for each input file. The estimated ReplayGain and peak are written to the output file ('TXXX').
+// The input files are read twice and must rewind once their end is reached.
+func Normalize(include func(index int) bool, visitor normalizeVisitor) Option {
+	return func() (stage, string, jobProcessor) {
+		return stageInit, "normalize", func(j *job) error {
+			j.normalize = &normalize{include: include, visitor: visitor}
+
+			return nil
+		}
+	}
+}
+
+// analyze estimates the loudness of the included input files and calculates the change of the
+// global gain for each input file.
+func (n *normalize) analyze(inputs []io.Reader) {
+	levels := make([]float64, len(inputs))
+	peaks := make([]int, len(inputs))
+	analyzed := make([]bool, len(inputs))
+
+	var sum float64
+	var count int
+	for i, r := range inputs {
+		if !n.include(i) {
+			continue
+		}
+
+		levels[i], peaks[i], analyzed[i] = averageGlobalGain(r)
+		if analyzed[i] {
+			sum += levels[i]
+			count++
+		}
+	}
+
+	n.steps = make([]int, len(inputs))
+	if count == 0 {
+		return
+	}
+
+	n.level = sum / float64(count)
+	for i := range inputs {
+		if analyzed[i] {
+			n.steps[i] = int(math.Round(n.level - levels[i]))
+
+			peak := peaks[i] + n.steps[i]
+			if peak > maxGlobalGain {
+				peak = maxGlobalGain
+			}
+			if peak > n.peak {
+				n.peak = peak
+			}
+		}
+
+		if n.include(i) {
+			n.visitor(i, float64(n.steps[i])*decibelsPerGlobalGainStep)
+		}
+	}
+}
+
+// averageGlobalGain returns the average and the maximum global gain of the granules with audio
+// data of a stream.
+func averageGlobalGain(r io.Reader) (float64, int, bool) {
+	var sum, count, max int
+	for {
+		frame := mp3lib.NextFrame(r)
+		if frame == nil {
+			break
+		}
+
+		granules, _ := sideInfoGranules(frame)
+		for _, g := range granules {
+			if g.hasData {
+				sum += g.globalGain
+				count++
+
+				if g.globalGain > max {
+					max = g.globalGain
+				}
+			}
+		}
+	}
+
+	if count == 0 {
+		return 0, 0, false
+	}
+
+	return float64(sum) / float64(count), max, true
+}
+
+// adjustGlobalGain changes the global gain of the granules with audio data of a Layer III frame
+// by the steps, limited to the valid range. The CRC is only updated if it was valid, a wrong CRC
+// stays wrong (e.g. to detect a damaged frame later).
+func adjustGlobalGain(frame *mp3lib.MP3Frame, steps int) {
+	granules, ok := sideInfoGranules(frame)
+	if !ok || steps == 0 {
+		return
+	}
+
+	validCRC := hasValidCRC(frame)
+
+	w := &bitReader{data: frame.RawBytes}
+	for _, g := range granules {
+		if !g.hasData {
+			continue
+		}
+
+		gain := g.globalGain + steps
+		if gain < 0 {
+			gain = 0
+		} else if gain > maxGlobalGain {
+			gain = maxGlobalGain
+		}
+
+		w.write(g.globalGainPos, globalGainBits, gain)
+	}
+
+	if validCRC {
+		updateCRC(frame)
+	}
+}
+
+// writeReplayGain writes the estimated ReplayGain and peak of the normalized output file ('TXXX'),
+// values copied from an input file are replaced.
+func writeReplayGain() (stage, string, jobProcessor) {
+	return stageCopyMetadata, "writing replay gain", func(j *job) error {
+		if j.normalize == nil || j.normalize.level == 0 {
+			return nil
+		}
+
+		gain := fmt.Sprintf("%+.2f dB", (referenceGlobalGain-j.normalize.level)*decibelsPerGlobalGainStep)
+		// a step of the global gain scales the samples by 2^(1/4)
+		peak := fmt.Sprintf("%.6f", math.Pow(2, float64(j.normalize.peak-fullScaleGlobalGain)/4))
+
+		for _, v := range []struct{ description, value string }{
+			{replayGainTrackGain, gain},
+			{replayGainTrackPeak, peak},
+		} {
+			key := tags.Key{ID: tags.IdUserDefinedText, Description: v.description}
+
+			frame, err := frameFor(key, v.value, j.tag.DefaultEncoding())
+			if err != nil {
+				return err
+			}
+
+			j.tagApplyVisitor(key.String(), v.value, nil)
+			deleteFrame(j.tag, key)
+			j.tag.AddFrame(key.ID, frame)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,212 @@
+package mp3binder
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/crra/id3v2/v2"
+	"github.com/crra/mp3binder/mp3binder/tags"
+	"github.com/dmulholl/mp3lib"
+	"github.com/stretchr/testify/assert"
+)
+
+// globalGains returns the global gain of the granules of the frame.
+func globalGains(t *testing.T, frame *mp3lib.MP3Frame) []int {
+	t.Helper()
+
+	granules, ok := sideInfoGranules(frame)
+	if !assert.True(t, ok) {
+		return nil
+	}
+
+	gains := make([]int, len(granules))
+	for i, g := range granules {
+		gains[i] = g.globalGain
+	}
+
+	return gains
+}
+
+func TestAnalyze(t *testing.T) {
+	t.Parallel()
+
+	silence := makeFrame(t, header44100)
+	quiet := makeAudibleFrame(t, header44100, 1000, 100, 158)
+	louder := makeAudibleFrame(t, header44100, 1000, 100, 162)
+	loud := makeAudibleFrame(t, header44100, 1000, 100, 170)
+	spacer := makeAudibleFrame(t, header44100, 1000, 100, 100)
+
+	inputs := []io.Reader{
+		// average of 160, the silence is ignored
+		bytes.NewReader(stream(silence, quiet, louder, silence)),
+		bytes.NewReader(stream(loud, loud)),
+		bytes.NewReader(stream(spacer)),
+		bytes.NewReader(stream(silence)),
+	}
+
+	adjustments := make(map[int]float64)
+	n := &normalize{
+		include: func(index int) bool { return index != 2 },
+		visitor: func(index int, adjustment float64) { adjustments[index] = adjustment },
+	}
+
+	n.analyze(inputs)
+
+	assert.Equal(t, []int{5, -5, 0, 0}, n.steps)
+	assert.Equal(t, map[int]float64{0: 5 * decibelsPerGlobalGainStep, 1: -5 * decibelsPerGlobalGainStep, 3: 0}, adjustments)
+}
+
+func TestAnalyzeWithoutAudio(t *testing.T) {
+	t.Parallel()
+
+	n := &normalize{
+		include: func(int) bool { return true },
+		visitor: func(int, float64) { assert.Fail(t, "no adjustment without audio data") },
+	}
+
+	n.analyze([]io.Reader{bytes.NewReader(stream(makeFrame(t, header44100)))})
+	assert.Equal(t, []int{0}, n.steps)
+}
+
+func TestAdjustGlobalGain(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []struct {
+		name     string
+		header   []byte
+		gain     int
+		steps    int
+		expected int
+	}{
+		{name: "louder", header: header44100, gain: 160, steps: 4, expected: 164},
+		{name: "quieter", header: header44100, gain: 160, steps: -4, expected: 156},
+		{name: "unchanged", header: header44100, gain: 160, steps: 0, expected: 160},
+		{name: "maximum", header: header44100, gain: 250, steps: 10, expected: maxGlobalGain},
+		{name: "minimum", header: header44100, gain: 5, steps: -10, expected: 0},
+		{name: "mono", header: header22050Mono, gain: 160, steps: 4, expected: 164},
+		{name: "with CRC", header: header44100CRC, gain: 160, steps: -4, expected: 156},
+	} {
+		f := f // pin
+		t.Run(f.name, func(t *testing.T) {
+			t.Parallel()
+
+			frame := makeAudibleFrame(t, f.header, 1000, 100, f.gain)
+			before := append([]byte{}, frame.RawBytes...)
+
+			adjustGlobalGain(frame, f.steps)
+
+			for _, gain := range globalGains(t, frame) {
+				assert.Equal(t, f.expected, gain)
+			}
+
+			assert.True(t, hasValidCRC(frame))
+			assert.Len(t, frame.RawBytes, len(before))
+			assert.Equal(t, before[sideInfoOffset(frame)+getSideInfoSize(frame):], frame.RawBytes[sideInfoOffset(frame)+getSideInfoSize(frame):], "the main data is unchanged")
+		})
+	}
+}
+
+func TestAdjustGlobalGainKeepsGranulesWithoutData(t *testing.T) {
+	t.Parallel()
+
+	frame := makeFrame(t, header44100CRC)
+	granules, ok := sideInfoGranules(frame)
+	if !assert.True(t, ok) {
+		return
+	}
+
+	// only the first granule has audio data
+	w := &bitReader{data: frame.RawBytes}
+	for _, g := range granules {
+		w.write(g.globalGainPos, globalGainBits, 150)
+	}
+	w.write(granules[0].globalGainPos-9-12, 12, 1000)
+	updateCRC(frame)
+
+	adjustGlobalGain(frame, 6)
+
+	assert.Equal(t, []int{156, 150, 150, 150}, globalGains(t, frame))
+	assert.True(t, hasValidCRC(frame))
+}
+
+func TestAdjustGlobalGainUpdatesCRC(t *testing.T) {
+	t.Parallel()
+
+	frame := makeAudibleFrame(t, header44100CRC, 1000, 100, 160)
+	crc := append([]byte{}, frame.RawBytes[crcOffset:crcOffset+2]...)
+
+	adjustGlobalGain(frame, 3)
+
+	assert.NotEqual(t, crc, frame.RawBytes[crcOffset:crcOffset+2])
+	assert.True(t, hasValidCRC(frame))
+
+	adjustGlobalGain(frame, -3)
+	assert.Equal(t, crc, frame.RawBytes[crcOffset:crcOffset+2])
+}
+
+func TestAdjustGlobalGainKeepsWrongCRC(t *testing.T) {
+	t.Parallel()
+
+	frame := flipBit(t, makeAudibleFrame(t, header44100CRC, 1000, 100, 160), (crcOffset+2)*8+20)
+	crc := append([]byte{}, frame.RawBytes[crcOffset:crcOffset+2]...)
+
+	adjustGlobalGain(frame, 3)
+
+	assert.Equal(t, crc, frame.RawBytes[crcOffset:crcOffset+2])
+	assert.False(t, hasValidCRC(frame))
+}
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	quiet := makeAudibleFrame(t, header44100CRC, 1000, 100, 160)
+	loud := makeAudibleFrame(t, header44100CRC, 1000, 100, 170)
+
+	var adjustments []float64
+	output, err := bind(t, [][]byte{stream(quiet, quiet), stream(loud, loud)},
+		Normalize(func(int) bool { return true }, func(index int, adjustment float64) {
+			adjustments = append(adjustments, adjustment)
+		}))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []float64{5 * decibelsPerGlobalGainStep, -5 * decibelsPerGlobalGainStep}, adjustments)
+
+		frames := audioFrames(t, output)
+		assert.Len(t, frames, 4)
+		for _, frame := range frames {
+			assert.Equal(t, []int{165, 165, 165, 165}, globalGains(t, frame))
+			assert.True(t, hasValidCRC(frame))
+		}
+	}
+}
+
+func TestNormalizeWritesReplayGain(t *testing.T) {
+	t.Parallel()
+
+	quiet := makeAudibleFrame(t, header44100, 1000, 100, 160)
+	loud := makeAudibleFrame(t, header44100, 1000, 100, 170)
+
+	output, err := bind(t, [][]byte{stream(quiet, quiet), stream(loud, loud)},
+		Normalize(func(int) bool { return true }, func(int, float64) {}))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	tag, err := id3v2.ParseReader(bytes.NewReader(output), id3v2.Options{Parse: true})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	values := make(map[string]string)
+	for _, f := range tag.GetFrames(tags.IdUserDefinedText) {
+		if udf, ok := f.(id3v2.UserDefinedTextFrame); ok {
+			values[udf.Description] = udf.Value
+		}
+	}
+
+	// a level of 165 is 5 steps below the reference, the loudest granules are 15 steps below full scale
+	assert.Equal(t, map[string]string{
+		"REPLAYGAIN_TRACK_GAIN": "+7.53 dB",
+		"REPLAYGAIN_TRACK_PEAK": "0.074325",
+	}, values)
+}
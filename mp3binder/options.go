@@ -2,11 +2,14 @@
 package mp3binder
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"sort"
 	"time"
 
 	"github.com/crra/id3v2/v2"
+	"github.com/crra/mp3binder/mp3binder/tags"
 )
 
 // stage defines an action sequence.
@@ -84,6 +87,17 @@ func TagCopyVisitor(f tagCopyVisitor) Option {
 	}
 }
 
+// ChapterArtwork embeds the front cover and the links (WXXX) of each input file in its chapter.
+func ChapterArtwork() Option {
+	return func() (stage, string, jobProcessor) {
+		return stageInit, "chapter artwork", func(j *job) error {
+			j.chapterArtwork = true
+
+			return nil
+		}
+	}
+}
+
 // MetadataVisitor registers a callback to receive the parsed metadata of the
 // media files.
 func MetadataVisitor(f metadataVisitor) Option {
@@ -129,12 +143,67 @@ func CopyMetadataFrom(index int, errNoTagsInTemplate error) Option {
 	}
 }
 
+// MergeMetadata merges the text metadata of all included input files to the output file. The strategy
+// for each tag is resolved by a callback function, tags without a strategy are not merged.
+func MergeMetadata(strategyFor func(id string) (MergeStrategy, bool), separator string, include func(index int) bool) Option {
+	return func() (stage, string, jobProcessor) {
+		return stageCopyMetadata, "merge metadata", func(j *job) error {
+			values := make(map[string][]string)
+
+			for i, t := range j.metadata {
+				if !include(i) {
+					continue
+				}
+
+				for id, value := range tagToMap(t) {
+					values[id] = append(values[id], value)
+				}
+			}
+
+			ids := make([]string, 0, len(values))
+			for id := range values {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+
+			for _, id := range ids {
+				strategy, ok := strategyFor(id)
+				if !ok {
+					continue
+				}
+
+				value := mergeValues(strategy, values[id], separator)
+				if value == "" {
+					continue
+				}
+
+				key := tags.ParseKey(id)
+
+				frame, err := frameFor(key, value, j.tag.DefaultEncoding())
+				if err != nil {
+					j.tagCopyVisitor(id, value, fmt.Errorf("tag '%s': %w", id, err))
+					continue
+				}
+
+				j.tagCopyVisitor(fmt.Sprintf("%s (%s)", id, strategy), value, nil)
+				j.tag.AddFrame(key.ID, frame)
+			}
+
+			return nil
+		}
+	}
+}
+
 // ApplyTextMetadata applies key/value pairs of text as metadata to the bounded file.
+// Keys can reference user defined text frames ('TXXX:description'), comments and lyrics ('COMM:language:description'),
+// links ('WXXX:description', 'WOAR') and popularimeters ('POPM:email' with 'rating/counter').
 func ApplyTextMetadata(f func(map[string]string) map[string]string) Option {
 	return func() (stage, string, jobProcessor) {
 		return stageApplyMetadata, "applying text metadata", func(j *job) error {
 			for id, value := range f(tagToMap(j.tag)) {
-				description, err := j.tagResolver.DescriptionFor(id)
+				key := tags.ParseKey(id)
+
+				description, err := j.tagResolver.DescriptionFor(key.ID)
 				if err != nil {
 					j.tagApplyVisitor(id, "", fmt.Errorf("tag '%s': %w", id, err))
 				} else {
@@ -142,11 +211,17 @@ func ApplyTextMetadata(f func(map[string]string) map[string]string) Option {
 				}
 
 				if value == "" {
-					j.tag.DeleteFrames(id)
+					deleteFrame(j.tag, key)
+					continue
+				}
+
+				frame, err := frameFor(key, value, j.tag.DefaultEncoding())
+				if err != nil {
+					j.tagApplyVisitor(id, value, fmt.Errorf("tag '%s': %w", id, err))
 					continue
 				}
 
-				j.tag.AddFrame(id, &id3v2.TextFrame{Encoding: j.tag.DefaultEncoding(), Text: value})
+				j.tag.AddFrame(key.ID, frame)
 			}
 
 			return nil
@@ -158,21 +233,27 @@ const coverType = "Front cover"
 
 // Cover assigns a file as cover to the bounded file.
 func Cover(mimeType string, r io.Reader) Option {
+	return Picture(id3v2.PTFrontCover, coverType, mimeType, r)
+}
+
+// Picture attaches an image with a picture type (e.g. id3v2.PTBackCover) to the bounded file.
+// The description identifies the picture.
+func Picture(pictureType byte, description, mimeType string, r io.Reader) Option {
 	return func() (stage, string, jobProcessor) {
-		return stageApplyMetadata, "adding cover", func(j *job) error {
-			frontCoverPicture, err := io.ReadAll(r)
+		return stageApplyMetadata, "adding picture", func(j *job) error {
+			picture, err := io.ReadAll(r)
 			if err != nil {
 				return err
 			}
 
-			j.tagApplyVisitor(coverType, mimeType, nil)
+			j.tagApplyVisitor(description, mimeType, nil)
 
 			j.tag.AddAttachedPicture(id3v2.PictureFrame{
 				Encoding:    j.tag.DefaultEncoding(),
 				MimeType:    mimeType,
-				PictureType: id3v2.PTFrontCover,
-				Description: coverType,
-				Picture:     frontCoverPicture,
+				PictureType: pictureType,
+				Description: description,
+				Picture:     picture,
 			})
 
 			return nil
@@ -180,42 +261,115 @@ func Cover(mimeType string, r io.Reader) Option {
 	}
 }
 
+// CoverFromInputs uses the first front cover embedded in the included input files as cover,
+// if the bounded file has none (e.g. set by Cover or copied from an input file). The picture
+// can be processed (e.g. resized) before it is added. A picture that can't be processed (e.g.
+// a broken image) is passed to invalid and skipped.
+func CoverFromInputs(include func(index int) bool, process func(mimeType string, r io.Reader) ([]byte, string, error), invalid func(index int, err error)) Option {
+	return func() (stage, string, jobProcessor) {
+		return stageApplyMetadata, "adding cover from input files", func(j *job) error {
+			if frontCoverOf(j.tag) != nil {
+				return nil
+			}
+
+			for i, t := range j.metadata {
+				if !include(i) {
+					continue
+				}
+
+				pf := frontCoverOf(t)
+				if pf == nil {
+					continue
+				}
+
+				picture, mimeType, err := process(pf.MimeType, bytes.NewReader(pf.Picture))
+				if err != nil {
+					invalid(i, err)
+					continue
+				}
+
+				j.tagApplyVisitor(fmt.Sprintf("%s (from input file %d)", coverType, i+1), mimeType, nil)
+
+				j.tag.AddAttachedPicture(id3v2.PictureFrame{
+					Encoding:    j.tag.DefaultEncoding(),
+					MimeType:    mimeType,
+					PictureType: id3v2.PTFrontCover,
+					Description: coverType,
+					Picture:     picture,
+				})
+
+				return nil
+			}
+
+			return nil
+		}
+	}
+}
+
+// ExtractCover passes the front cover of the bounded file to a callback (e.g. to save it to disk).
+// If the bounded file has no cover, noCover is called instead (e.g. to warn about it).
+func ExtractCover(extract func(mimeType string, picture []byte) error, noCover func()) Option {
+	return func() (stage, string, jobProcessor) {
+		return stageWriteMetadata, "extracting cover", func(j *job) error {
+			pf := frontCoverOf(j.tag)
+			if pf == nil {
+				noCover()
+				return nil
+			}
+
+			return extract(pf.MimeType, pf.Picture)
+		}
+	}
+}
+
 // Chapters uses a callback function to resolve the title of the chapter for a file that bound.
-func Chapters(resolveFunc func(index int, chapterIndex int) (bool, string)) Option {
+// The callback receives the duration of the file to be able to use it in the title.
+func Chapters(resolveFunc func(index int, chapterIndex int, duration time.Duration) (bool, string)) Option {
 	return func() (stage, string, jobProcessor) {
 		return stageBuildChapers, "adding chapters", func(j *job) error {
 			var start time.Duration
 
 			chaptersIds := make([]string, 0, len(j.metadata))
 			chapterIndex := 1
+			var previousPicture *id3v2.PictureFrame
 			for i, numberOfFiles := 0, len(j.inputDurations); i < numberOfFiles; i++ {
 				end := start + j.inputDurations[i]
 
-				createChapter, chapterTitle := resolveFunc(i, chapterIndex)
+				createChapter, chapterTitle := resolveFunc(i, chapterIndex, j.inputDurations[i])
 
 				if !createChapter {
-					// skip (e.g. due to an interlace file)
+					// skip (e.g. due to an interlace file), the next chapter starts after it
+					start = end + j.gapDurations[i]
 					continue
 				}
 
 				chapterId := fmt.Sprintf("c%d", chapterIndex)
 
-				j.tag.AddChapterFrame(id3v2.ChapterFrame{
-					ElementID:   chapterId,
-					StartTime:   start,
-					EndTime:     end,
-					StartOffset: id3v2.IgnoredOffset,
-					EndOffset:   id3v2.IgnoredOffset,
-					Title: &id3v2.TextFrame{
-						Encoding: id3v2.EncodingUTF8,
-						Text:     chapterTitle,
+				chapter := chapterFrame{
+					ChapterFrame: id3v2.ChapterFrame{
+						ElementID:   chapterId,
+						StartTime:   start,
+						EndTime:     end,
+						StartOffset: id3v2.IgnoredOffset,
+						EndOffset:   id3v2.IgnoredOffset,
+						Title: &id3v2.TextFrame{
+							Encoding: id3v2.EncodingUTF8,
+							Text:     chapterTitle,
+						},
 					},
-				})
+				}
+
+				if j.chapterArtwork {
+					chapter.subFrames, previousPicture = chapterArtwork(j.metadata[i], j.tag, previousPicture)
+				}
+
+				j.tag.AddFrame(j.tag.CommonID("Chapters"), chapter)
 
 				j.tagApplyVisitor(fmt.Sprintf("Chapter: %d from '%s' to '%s'", chapterIndex, start.Round(time.Second), end.Round(time.Second)), chapterTitle, nil)
 
 				chaptersIds = append(chaptersIds, chapterId)
-				start = end
+				// the chapter ends before the gap, the next chapter starts after it
+				start = end + j.gapDurations[i]
 				chapterIndex++
 			}
 
@@ -0,0 +1,48 @@
+package mp3binder
+
+import (
+	"time"
+
+	"github.com/dmulholl/mp3lib"
+)
+
+const (
+	// header bits that are cleared for a silent frame
+	headerNoCRCProtection = 0x01
+	headerPaddingBit      = 0x02
+)
+
+// Gap puts silence between the input files. The callback returns the duration of the silence after
+// the input file at the index (e.g. zero for none). The silence consists of empty frames with the
+// stream parameters (e.g. sampling rate, channel mode and bitrate) of the last frame before the gap.
+func Gap(after func(index int) time.Duration) Option {
+	return func() (stage, string, jobProcessor) {
+		return stageInit, "gap", func(j *job) error {
+			j.gapAfter = after
+
+			return nil
+		}
+	}
+}
+
+// silentFrame returns a frame with the header of the template frame without CRC protection and
+// padding. The side information and the main data are zeroed, which decodes to silence.
+func silentFrame(template *mp3lib.MP3Frame) []byte {
+	frame := make([]byte, (template.SampleCount/8)*template.BitRate/template.SamplingRate)
+	copy(frame, template.RawBytes[:4])
+
+	frame[1] |= headerNoCRCProtection
+	frame[2] &^= headerPaddingBit
+
+	return frame
+}
+
+// silentFrames returns the number of frames of the template needed to cover the duration.
+func silentFrames(template *mp3lib.MP3Frame, d time.Duration) int {
+	frameDuration := duration(template)
+	if d <= 0 || frameDuration <= 0 {
+		return 0
+	}
+
+	return int((d + frameDuration - 1) / frameDuration)
+}
@@ -0,0 +1,89 @@
+package mp3binder
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/dmulholl/mp3lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSilentFrame(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []struct {
+		name   string
+		header []byte
+		size   int
+	}{
+		{name: "44100 Hz", header: header44100, size: 417},
+		{name: "48000 Hz with CRC and padding", header: header48000CRCPadding, size: 384},
+		{name: "22050 Hz mono", header: header22050Mono, size: 208},
+	} {
+		f := f // pin
+		t.Run(f.name, func(t *testing.T) {
+			t.Parallel()
+
+			template := makeAudibleFrame(t, f.header, 1000, 100, 150)
+			silence := silentFrame(template)
+
+			assert.Len(t, silence, f.size)
+			assert.Equal(t, make([]byte, f.size-4), silence[4:], "side information and main data are zeroed")
+
+			frame := mp3lib.NextFrame(bytes.NewReader(silence))
+			if assert.NotNil(t, frame) {
+				assert.Equal(t, f.size, frame.FrameLength)
+				assert.False(t, frame.CrcProtection)
+				assert.False(t, frame.PaddingBit)
+				assert.Equal(t, template.MPEGVersion, frame.MPEGVersion)
+				assert.Equal(t, template.BitRate, frame.BitRate)
+				assert.Equal(t, template.SamplingRate, frame.SamplingRate)
+				assert.Equal(t, template.ChannelMode, frame.ChannelMode)
+				assert.Equal(t, template.RawBytes[3], silence[3])
+			}
+		})
+	}
+}
+
+func TestSilentFrames(t *testing.T) {
+	t.Parallel()
+
+	template := makeFrame(t, header44100)
+	frameDuration := duration(template)
+
+	assert.Equal(t, 0, silentFrames(template, 0))
+	assert.Equal(t, 0, silentFrames(template, -time.Second))
+	assert.Equal(t, 1, silentFrames(template, time.Millisecond))
+	assert.Equal(t, 1, silentFrames(template, frameDuration))
+	assert.Equal(t, 2, silentFrames(template, frameDuration+1))
+	assert.Equal(t, 39, silentFrames(template, time.Second))
+}
+
+func TestGap(t *testing.T) {
+	t.Parallel()
+
+	audible := makeAudibleFrame(t, header44100, 1000, 100, 150)
+	input := stream(repeat(audible, 3)...)
+
+	output, err := bind(t, [][]byte{input, input, input}, Gap(func(index int) time.Duration {
+		if index == 0 {
+			return time.Second
+		}
+
+		return 0
+	}))
+	if assert.NoError(t, err) {
+		frames := audioFrames(t, output)
+		if assert.Len(t, frames, 3+39+3+3) {
+			silence := silentFrame(audible)
+			for i, frame := range frames {
+				if i >= 3 && i < 3+39 {
+					assert.Equal(t, silence, frame.RawBytes, "frame %d", i)
+				} else {
+					assert.Equal(t, audible.RawBytes, frame.RawBytes, "frame %d", i)
+				}
+			}
+		}
+	}
+}
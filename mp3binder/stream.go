@@ -0,0 +1,114 @@
+package mp3binder
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dmulholl/mp3lib"
+)
+
+// StreamInfo describes the format of the frames of a mp3 file.
+type StreamInfo struct {
+	Version      byte
+	Layer        byte
+	SamplingRate int
+	Mono         bool
+	// BitRate is zero for a variable bitrate
+	BitRate  int
+	Duration time.Duration
+}
+
+var (
+	versionNames = map[byte]string{mp3lib.MPEGVersion1: "MPEG-1", mp3lib.MPEGVersion2: "MPEG-2", mp3lib.MPEGVersion2_5: "MPEG-2.5"}
+	layerNames   = map[byte]string{mp3lib.MPEGLayerI: "Layer I", mp3lib.MPEGLayerII: "Layer II", mp3lib.MPEGLayerIII: "Layer III"}
+)
+
+// ReadStreamInfo reads the format and the duration of the frames of a mp3 file. It returns false
+// if the file has no frames.
+func ReadStreamInfo(r io.Reader) (StreamInfo, bool) {
+	s := &streamScanner{}
+
+	for {
+		frame := mp3lib.NextFrame(r)
+		if frame == nil {
+			break
+		}
+
+		if s.frames == 0 && (mp3lib.IsXingHeader(frame) || mp3lib.IsVbriHeader(frame)) {
+			continue
+		}
+
+		s.add(frame)
+	}
+
+	return s.info()
+}
+
+// streamScanner collects the format and the duration of the audio frames of a stream.
+type streamScanner struct {
+	stream   StreamInfo
+	frames   int
+	bytes    int
+	variable bool
+}
+
+func (s *streamScanner) add(frame *mp3lib.MP3Frame) {
+	if s.frames == 0 {
+		s.stream.Version = frame.MPEGVersion
+		s.stream.Layer = frame.MPEGLayer
+		s.stream.SamplingRate = frame.SamplingRate
+		s.stream.Mono = frame.ChannelMode == mp3lib.Mono
+		s.stream.BitRate = frame.BitRate
+	} else if frame.BitRate != s.stream.BitRate {
+		s.variable = true
+	}
+
+	s.stream.Duration += duration(frame)
+	s.frames++
+	s.bytes += len(frame.RawBytes)
+}
+
+// info returns the stream information, false if there are no frames.
+func (s *streamScanner) info() (StreamInfo, bool) {
+	info := s.stream
+	if s.variable {
+		info.BitRate = 0
+	}
+
+	return info, s.frames > 0
+}
+
+// Matches returns true if the frames of both streams can be joined without changing the format
+// (e.g. the sampling rate). The bitrates are only compared if both are constant.
+func (s StreamInfo) Matches(o StreamInfo) bool {
+	if s.Version != o.Version || s.Layer != o.Layer || s.SamplingRate != o.SamplingRate || s.Mono != o.Mono {
+		return false
+	}
+
+	return s.BitRate == 0 || o.BitRate == 0 || s.BitRate == o.BitRate
+}
+
+// String implements the fmt.Stringer interface (e.g. 'MPEG-1 Layer III, 44100 Hz, stereo, 128 kbps').
+func (s StreamInfo) String() string {
+	bitRate := "variable bitrate"
+	if s.BitRate > 0 {
+		bitRate = fmt.Sprintf("%d kbps", s.BitRate/1000)
+	}
+
+	return fmt.Sprintf("%s, %d Hz, %s, %s", s.Format(), s.SamplingRate, s.Channels(), bitRate)
+}
+
+// Format returns the version and the layer (e.g. 'MPEG-1 Layer III').
+func (s StreamInfo) Format() string {
+	return versionNames[s.Version] + " " + layerNames[s.Layer]
+}
+
+// Channels returns 'mono' or 'stereo'.
+func (s StreamInfo) Channels() string {
+	if s.Mono {
+		return "mono"
+	}
+
+	return "stereo"
+}
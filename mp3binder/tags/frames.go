@@ -0,0 +1,182 @@
+package tags
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// multipleValuesSeparator separates the values of a text frame in id3v2.4.
+const multipleValuesSeparator = "\x00"
+
+// Frame describes a frame in the registry of a resolver: its description and the rules
+// for its values. Validate and Normalize are optional and receive a single value of
+// text frames that hold multiple values.
+type Frame struct {
+	ID          string
+	Description string
+	Validate    func(value string) error
+	Normalize   func(value string) string
+}
+
+// v24Frames are the rules for the values of the id3v2.4 frames.
+var v24Frames = []Frame{
+	{ID: "TRCK", Validate: validatePosition, Normalize: normalizePosition},
+	{ID: "TPOS", Validate: validatePosition, Normalize: normalizePosition},
+	{ID: "TDRC", Validate: validateTimestamp, Normalize: normalizeTimestamp},
+	{ID: "TDOR", Validate: validateTimestamp, Normalize: normalizeTimestamp},
+	{ID: "TDRL", Validate: validateTimestamp, Normalize: normalizeTimestamp},
+	{ID: "TDEN", Validate: validateTimestamp, Normalize: normalizeTimestamp},
+	{ID: "TDTG", Validate: validateTimestamp, Normalize: normalizeTimestamp},
+	{ID: "TLAN", Validate: validateLanguage, Normalize: strings.ToLower},
+	{ID: "TCON", Validate: validateGenre, Normalize: normalizeGenre},
+	{ID: "TBPM", Validate: validateNumber},
+	{ID: "TLEN", Validate: validateNumber},
+}
+
+var positionPattern = regexp.MustCompile(`^(\d+)(?:/(\d+))?$`)
+
+// validatePosition accepts the position in a set in the format 'n[/m]' (e.g. '3/12').
+func validatePosition(value string) error {
+	matches := positionPattern.FindStringSubmatch(value)
+	if matches == nil {
+		return errors.New("must be in the format 'n[/m]' (e.g. '3/12')")
+	}
+
+	if matches[2] != "" {
+		n, _ := strconv.Atoi(matches[1])
+		m, _ := strconv.Atoi(matches[2])
+		if n > m {
+			return fmt.Errorf("position %d exceeds the total of %d", n, m)
+		}
+	}
+
+	return nil
+}
+
+func normalizePosition(value string) string {
+	n, m, hasTotal := strings.Cut(strings.ReplaceAll(value, " ", ""), "/")
+	n = strings.TrimLeft(n, "0")
+	if n == "" {
+		n = "0"
+	}
+
+	if !hasTotal {
+		return n
+	}
+
+	m = strings.TrimLeft(m, "0")
+	if m == "" {
+		m = "0"
+	}
+
+	return n + "/" + m
+}
+
+// timestampLayouts are the subset of ISO-8601 used by id3v2.4 (yyyy[-MM[-dd[THH[:mm[:ss]]]]]).
+var timestampLayouts = []string{
+	"2006",
+	"2006-01",
+	"2006-01-02",
+	"2006-01-02T15",
+	"2006-01-02T15:04",
+	"2006-01-02T15:04:05",
+}
+
+func validateTimestamp(value string) error {
+	for _, layout := range timestampLayouts {
+		if len(layout) != len(value) {
+			continue
+		}
+
+		if _, err := time.Parse(layout, value); err == nil {
+			return nil
+		}
+	}
+
+	return errors.New("must be an ISO-8601 timestamp (e.g. '2021', '2021-05-01' or '2021-05-01T10:30')")
+}
+
+// normalizeTimestamp separates the date and time with 'T' (e.g. '2021-05-01 10:30').
+func normalizeTimestamp(value string) string {
+	return strings.Replace(value, " ", "T", 1)
+}
+
+// unknownLanguage is the language code for an unknown language.
+const unknownLanguage = "xxx"
+
+func validateLanguage(value string) error {
+	value = strings.ToLower(value)
+	if value == unknownLanguage {
+		return nil
+	}
+
+	if len(value) == languageLength {
+		if _, err := language.ParseBase(value); err == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("'%s' must be an ISO-639-2 language code (e.g. 'eng')", value)
+}
+
+// validateGenre accepts free text (e.g. 'Audiobook; Sci-Fi') as allowed by id3v2.4, only references
+// (e.g. '17' or '(17)') must be in the genre table.
+func validateGenre(value string) error {
+	if index, ok := genreReference(value); ok && (index < 0 || index >= len(genres)) {
+		return fmt.Errorf("'%s' is not a known genre", value)
+	}
+
+	return nil
+}
+
+// normalizeGenre replaces references (e.g. '17' or '(17)') and differently cased names with the name of the genre.
+func normalizeGenre(value string) string {
+	if genre, ok := genreOf(value); ok {
+		return genre
+	}
+
+	return value
+}
+
+// genreReference returns the index of a reference to the genre table (e.g. '17' or '(17)').
+func genreReference(value string) (int, bool) {
+	index, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(value, "("), ")"))
+
+	return index, err == nil
+}
+
+func genreOf(value string) (string, bool) {
+	if value == genreRemix || value == genreCover {
+		return value, true
+	}
+
+	if index, ok := genreReference(value); ok {
+		if index >= 0 && index < len(genres) {
+			return genres[index], true
+		}
+
+		return "", false
+	}
+
+	for _, genre := range genres {
+		if strings.EqualFold(genre, value) {
+			return genre, true
+		}
+	}
+
+	return "", false
+}
+
+func validateNumber(value string) error {
+	if _, err := strconv.ParseUint(value, 10, 64); err != nil {
+		return fmt.Errorf("'%s' must be a number", value)
+	}
+
+	return nil
+}
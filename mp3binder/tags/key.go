@@ -0,0 +1,114 @@
+package tags
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidValue = errors.New("invalid value")
+
+const (
+	keySeparator      = ":"
+	qualifiersOpen    = "["
+	qualifiersClose   = "]"
+	counterSeparator  = "/"
+	urlFramePrefix    = "W"
+	languageLength    = 3
+	maxPopularimeter  = 255
+	popularimeterBits = 8
+
+	IdComment         = "COMM"
+	IdLyrics          = "USLT"
+	IdUserDefinedText = "TXXX"
+	IdUserDefinedURL  = "WXXX"
+	IdPopularimeter   = "POPM"
+)
+
+// Key references a frame by its id and the qualifiers for frames that can be present
+// multiple times (e.g. 'TXXX:ASIN' or 'COMM:eng:description'). For popularimeters
+// the description holds the email (e.g. 'POPM:user@example.com').
+type Key struct {
+	ID          string
+	Language    string
+	Description string
+}
+
+// ParseKey parses the textual representation of a key. The qualifiers follow the
+// id separated by colons ('COMM:eng:description') or in brackets ('COMM[eng:description]').
+// Comments and lyrics take a language and a description, user defined text and url frames
+// take a description and popularimeters an email.
+func ParseKey(s string) Key {
+	s = strings.TrimSpace(s)
+
+	var id, qualifiers string
+	if open := strings.Index(s, qualifiersOpen); open > 0 && strings.HasSuffix(s, qualifiersClose) {
+		id, qualifiers = s[:open], s[open+1:len(s)-1]
+	} else {
+		id, qualifiers, _ = strings.Cut(s, keySeparator)
+	}
+
+	k := Key{ID: id}
+
+	if hasLanguage(id) {
+		k.Language, k.Description, _ = strings.Cut(qualifiers, keySeparator)
+	} else {
+		k.Description = qualifiers
+	}
+
+	return k
+}
+
+// String returns the textual representation of the key.
+func (k Key) String() string {
+	switch {
+	case hasLanguage(k.ID) && (k.Language != "" || k.Description != ""):
+		return k.ID + keySeparator + k.Language + keySeparator + k.Description
+	case k.Description != "":
+		return k.ID + keySeparator + k.Description
+	default:
+		return k.ID
+	}
+}
+
+// HasQualifiers returns true if the key references a specific frame of an id.
+func (k Key) HasQualifiers() bool {
+	return k.Language != "" || k.Description != ""
+}
+
+// IsURL returns true if the key references a frame that holds an url (e.g. 'WOAR').
+func (k Key) IsURL() bool {
+	return strings.HasPrefix(k.ID, urlFramePrefix)
+}
+
+// IsLongText returns true if the key references a frame that usually holds long texts
+// (e.g. lyrics or comments).
+func (k Key) IsLongText() bool {
+	return k.ID == IdComment || k.ID == IdLyrics
+}
+
+func hasLanguage(id string) bool {
+	return id == IdComment || id == IdLyrics
+}
+
+// ParsePopularimeter parses the value of a popularimeter in the format 'rating[/counter]'
+// (e.g. '196' or '196/12').
+func ParsePopularimeter(value string) (uint8, *big.Int, error) {
+	ratingStr, counterStr, hasCounter := strings.Cut(strings.TrimSpace(value), counterSeparator)
+
+	rating, err := strconv.ParseUint(strings.TrimSpace(ratingStr), 10, popularimeterBits)
+	if err != nil {
+		return 0, nil, fmt.Errorf("rating '%s' must be between 0 and %d: %w", ratingStr, maxPopularimeter, ErrInvalidValue)
+	}
+
+	counter := big.NewInt(0)
+	if hasCounter {
+		if _, ok := counter.SetString(strings.TrimSpace(counterStr), 10); !ok || counter.Sign() < 0 {
+			return 0, nil, fmt.Errorf("counter '%s' must be a positive number: %w", counterStr, ErrInvalidValue)
+		}
+	}
+
+	return uint8(rating), counter, nil
+}
@@ -0,0 +1,51 @@
+package tags
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKey(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []struct {
+		input     string
+		expected  Key
+		canonical string
+	}{
+		{input: "TALB", expected: Key{ID: "TALB"}, canonical: "TALB"},
+		{input: "TXXX:ASIN", expected: Key{ID: "TXXX", Description: "ASIN"}, canonical: "TXXX:ASIN"},
+		{input: "TXXX[ASIN]", expected: Key{ID: "TXXX", Description: "ASIN"}, canonical: "TXXX:ASIN"},
+		{input: "COMM:eng:desc", expected: Key{ID: "COMM", Language: "eng", Description: "desc"}, canonical: "COMM:eng:desc"},
+		{input: "COMM[eng:desc]", expected: Key{ID: "COMM", Language: "eng", Description: "desc"}, canonical: "COMM:eng:desc"},
+		{input: "USLT[eng]", expected: Key{ID: "USLT", Language: "eng"}, canonical: "USLT:eng:"},
+		{input: "WXXX[a:b]", expected: Key{ID: "WXXX", Description: "a:b"}, canonical: "WXXX:a:b"},
+		{input: "POPM[user@example.com]", expected: Key{ID: "POPM", Description: "user@example.com"}, canonical: "POPM:user@example.com"},
+	} {
+		f := f // pin
+		t.Run(f.input, func(t *testing.T) {
+			t.Parallel()
+
+			key := ParseKey(f.input)
+			assert.Equal(t, f.expected, key)
+			assert.Equal(t, f.canonical, key.String())
+		})
+	}
+}
+
+func TestParsePopularimeter(t *testing.T) {
+	t.Parallel()
+
+	rating, counter, err := ParsePopularimeter("196/12")
+	if assert.NoError(t, err) {
+		assert.Equal(t, uint8(196), rating)
+		assert.Equal(t, big.NewInt(12), counter)
+	}
+
+	for _, invalid := range []string{"", "256", "-1", "a", "1/b", "1/-2"} {
+		_, _, err := ParsePopularimeter(invalid)
+		assert.ErrorIs(t, err, ErrInvalidValue, "Input: '%s'", invalid)
+	}
+}
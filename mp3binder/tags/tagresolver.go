@@ -1,31 +1,140 @@
 package tags
 
 import (
+	"fmt"
+	"net/url"
+	"strings"
+
 	"github.com/crra/id3v2/v2"
 )
 
+// v24URLIDs are the url frames of id3v2.4, which are not part of the common ids of the id3v2 library.
+var v24URLIDs = map[string]string{
+	"Commercial information":                   "WCOM",
+	"Copyright/Legal information":              "WCOP",
+	"Official audio file webpage":              "WOAF",
+	"Official artist/performer webpage":        "WOAR",
+	"Official audio source webpage":            "WOAS",
+	"Official Internet radio station homepage": "WORS",
+	"Payment":                     "WPAY",
+	"Publishers official webpage": "WPUB",
+	"User defined URL link frame": IdUserDefinedURL,
+}
+
+// tagResolver is a registry of the known frames and the rules for their values.
 type tagResolver struct {
-	errTagNonStandard error
-	knownTags         map[string]string
+	errTagNonStandard  error
+	errTagInvalid      error
+	errTagInvalidValue error
+	knownTags          map[string]Frame
 }
 
-func NewV24(errTagNonStandard error) *tagResolver {
-	knownTags := make(map[string]string, len(id3v2.V24CommonIDs))
-	for description, tagName := range id3v2.V24CommonIDs {
-		knownTags[tagName] = description
+// NewV24 creates a resolver for the frames of id3v2.4. Frames that can not be written
+// (e.g. a comment with an invalid language) are reported with errTagInvalid, values that
+// violate the rules of a frame (e.g. a track number that is not a number) with errTagInvalidValue.
+func NewV24(errTagNonStandard, errTagInvalid, errTagInvalidValue error) *tagResolver {
+	r := &tagResolver{
+		errTagNonStandard:  errTagNonStandard,
+		errTagInvalid:      errTagInvalid,
+		errTagInvalidValue: errTagInvalidValue,
+		knownTags:          make(map[string]Frame, len(id3v2.V24CommonIDs)+len(v24URLIDs)),
 	}
 
-	return &tagResolver{
-		errTagNonStandard: errTagNonStandard,
-		knownTags:         knownTags,
+	for _, ids := range []map[string]string{id3v2.V24CommonIDs, v24URLIDs} {
+		for description, tagName := range ids {
+			r.Register(Frame{ID: tagName, Description: description})
+		}
+	}
+
+	r.Register(v24Frames...)
+
+	return r
+}
+
+// Register adds frames to the registry. The rules of an already registered frame are
+// replaced, its description is kept if the frame has none.
+func (r *tagResolver) Register(frames ...Frame) {
+	for _, f := range frames {
+		if existing, exists := r.knownTags[f.ID]; exists && f.Description == "" {
+			f.Description = existing.Description
+		}
+
+		r.knownTags[f.ID] = f
 	}
 }
 
 func (r *tagResolver) DescriptionFor(id string) (string, error) {
-	description, exist := r.knownTags[id]
+	frame, exist := r.knownTags[id]
 	if !exist {
 		return "", r.errTagNonStandard
 	}
 
-	return description, nil
+	return frame.Description, nil
+}
+
+// Validate checks if a key and its value can be written as frame (e.g. the language of a
+// comment or the rating of a popularimeter) and if the value follows the rules of the frame.
+// An empty value is always valid as it removes the frame.
+func (r *tagResolver) Validate(key, value string) error {
+	k := ParseKey(key)
+
+	switch k.ID {
+	case IdComment, IdLyrics:
+		if k.Language != "" && len(k.Language) != languageLength {
+			return fmt.Errorf("tag '%s': language '%s' must have %d letters (ISO-639-2): %w", key, k.Language, languageLength, r.errTagInvalid)
+		}
+	case IdUserDefinedText, IdUserDefinedURL:
+		if k.Description == "" && value != "" {
+			return fmt.Errorf("tag '%s': requires a description (e.g. '%s[description]'): %w", key, k.ID, r.errTagInvalid)
+		}
+	case IdPopularimeter:
+		if k.Description == "" && value != "" {
+			return fmt.Errorf("tag '%s': requires an email (e.g. '%s[user@example.com]'): %w", key, k.ID, r.errTagInvalid)
+		}
+
+		if value != "" {
+			if _, _, err := ParsePopularimeter(value); err != nil {
+				return fmt.Errorf("tag '%s': %v: %w", key, err, r.errTagInvalid)
+			}
+		}
+	default:
+		if k.HasQualifiers() {
+			return fmt.Errorf("tag '%s': '%s' does not take qualifiers: %w", key, k.ID, r.errTagInvalid)
+		}
+	}
+
+	if value == "" {
+		return nil
+	}
+
+	if k.IsURL() {
+		if u, err := url.Parse(value); err != nil || u.Scheme == "" {
+			return fmt.Errorf("tag '%s': '%s' is not an url: %w", key, value, r.errTagInvalid)
+		}
+	}
+
+	if frame, exists := r.knownTags[k.ID]; exists && frame.Validate != nil {
+		for _, v := range strings.Split(value, multipleValuesSeparator) {
+			if err := frame.Validate(v); err != nil {
+				return fmt.Errorf("tag '%s': %v: %w", key, err, r.errTagInvalidValue)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Normalize returns the value in the canonical form of the frame (e.g. '3/12' for the track ' 03 / 12').
+func (r *tagResolver) Normalize(key, value string) string {
+	frame, exists := r.knownTags[ParseKey(key).ID]
+	if !exists || frame.Normalize == nil || value == "" {
+		return value
+	}
+
+	values := strings.Split(value, multipleValuesSeparator)
+	for i, v := range values {
+		values[i] = frame.Normalize(strings.TrimSpace(v))
+	}
+
+	return strings.Join(values, multipleValuesSeparator)
 }
@@ -0,0 +1,126 @@
+package tags
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	errTestNonStandard  = errors.New("non-standard")
+	errTestInvalid      = errors.New("invalid")
+	errTestInvalidValue = errors.New("invalid value")
+)
+
+func TestGenres(t *testing.T) {
+	t.Parallel()
+
+	assert.Len(t, genres, 192)
+	assert.Equal(t, "Rock", genres[17])
+	assert.Equal(t, "Psybient", genres[191])
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+	r := NewV24(errTestNonStandard, errTestInvalid, errTestInvalidValue)
+
+	for _, f := range []struct {
+		key   string
+		value string
+		err   error
+	}{
+		{key: "TRCK", value: "3"},
+		{key: "TRCK", value: "3/12"},
+		{key: "TRCK", value: "13/12", err: errTestInvalidValue},
+		{key: "TRCK", value: "3 of 12", err: errTestInvalidValue},
+		{key: "TPOS", value: "1/2"},
+		{key: "TDRC", value: "2021"},
+		{key: "TDRC", value: "2021-05-01T10:30:00"},
+		{key: "TDRC", value: "2021-13-01", err: errTestInvalidValue},
+		{key: "TDRC", value: "May 2021", err: errTestInvalidValue},
+		{key: "TLAN", value: "deu"},
+		{key: "TLAN", value: "eng\x00fra"},
+		{key: "TLAN", value: "en", err: errTestInvalidValue},
+		{key: "TLAN", value: "eng\x00zzz", err: errTestInvalidValue},
+		{key: "TCON", value: "Rock"},
+		{key: "TCON", value: "(17)"},
+		{key: "TCON", value: "RX"},
+		{key: "TCON", value: "192", err: errTestInvalidValue},
+		{key: "TCON", value: "(-1)", err: errTestInvalidValue},
+		{key: "TCON", value: "Audiobook; Sci-Fi"},
+		{key: "TCON", value: "Rock\x00(17)"},
+		{key: "TCON", value: "Rock\x00(192)", err: errTestInvalidValue},
+		{key: "TBPM", value: "120"},
+		{key: "TBPM", value: "fast", err: errTestInvalidValue},
+		{key: "TALB", value: "anything"},
+		{key: "TRCK", value: ""},
+		{key: "TALB:foo", value: "Album", err: errTestInvalid},
+		{key: "COMM:en:desc", value: "text", err: errTestInvalid},
+		{key: "TXXX", value: "text", err: errTestInvalid},
+		{key: "WOAR", value: "example.com", err: errTestInvalid},
+		{key: "POPM:user@example.com", value: "300", err: errTestInvalid},
+	} {
+		f := f // pin
+		t.Run(f.key+"="+f.value, func(t *testing.T) {
+			t.Parallel()
+
+			err := r.Validate(f.key, f.value)
+			if f.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, f.err)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+	r := NewV24(errTestNonStandard, errTestInvalid, errTestInvalidValue)
+
+	for _, f := range []struct {
+		key      string
+		value    string
+		expected string
+	}{
+		{key: "TRCK", value: " 03 / 12 ", expected: "3/12"},
+		{key: "TRCK", value: "007", expected: "7"},
+		{key: "TDRC", value: "2021-05-01 10:30", expected: "2021-05-01T10:30"},
+		{key: "TLAN", value: "ENG\x00Deu", expected: "eng\x00deu"},
+		{key: "TCON", value: "(17)", expected: "Rock"},
+		{key: "TCON", value: "hip-hop", expected: "Hip-Hop"},
+		{key: "TCON", value: "Unknown", expected: "Unknown"},
+		{key: "TALB", value: " As is ", expected: " As is "},
+	} {
+		f := f // pin
+		t.Run(f.key+"="+f.value, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, f.expected, r.Normalize(f.key, f.value))
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+	r := NewV24(errTestNonStandard, errTestInvalid, errTestInvalidValue)
+
+	_, err := r.DescriptionFor("XABC")
+	assert.ErrorIs(t, err, errTestNonStandard)
+
+	r.Register(Frame{ID: "XABC", Description: "Custom", Validate: validateNumber})
+	description, err := r.DescriptionFor("XABC")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Custom", description)
+	}
+	assert.ErrorIs(t, r.Validate("XABC", "a"), errTestInvalidValue)
+
+	// replacing the rules keeps the description
+	r.Register(Frame{ID: "TALB", Validate: validateNumber})
+	description, err = r.DescriptionFor("TALB")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Album/Movie/Show title", description)
+	}
+	assert.ErrorIs(t, r.Validate("TALB", "a"), errTestInvalidValue)
+}
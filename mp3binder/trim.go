@@ -0,0 +1,228 @@
+package mp3binder
+
+import (
+	"io"
+	"math"
+	"time"
+
+	"github.com/dmulholl/mp3lib"
+)
+
+// the global gain scales in steps of 2^(1/4)
+const globalGainStepsPerDecibel = 4 / 6.0206
+
+type (
+	trimVisitor func(index int, leading, trailing time.Duration)
+
+	// trim drops the leading and trailing silent frames of the included input files
+	trim struct {
+		// the threshold in steps of the global gain below the level of an input file
+		thresholdSteps int
+		include        func(index int) bool
+		visitor        trimVisitor
+		// the global gain below which a frame is silent for each input file
+		maxGlobalGains []int
+	}
+)
+
+// TrimSilence drops the leading and trailing frames of the included input files (e.g. not the
+// interlace files) that are silent. A frame is silent if its granules carry no audio data (e.g.
+// digital silence) or are quieter than the threshold in dB (e.g. -60) below the median loudness of
+// the input file. The loudness is estimated from the side information of Layer III frames without
+// decoding them. The visitor receives the removed durations for each input file. The input files
+// are read twice and must rewind once their end is reached.
+func TrimSilence(threshold float64, include func(index int) bool, visitor trimVisitor) Option {
+	return func() (stage, string, jobProcessor) {
+		return stageInit, "trim silence", func(j *job) error {
+			j.trim = &trim{
+				thresholdSteps: int(math.Round(threshold * globalGainStepsPerDecibel)),
+				include:        include,
+				visitor:        visitor,
+			}
+
+			return nil
+		}
+	}
+}
+
+// analyze calculates the global gain below which a frame is silent for each included input file.
+func (t *trim) analyze(inputs []io.Reader) {
+	t.maxGlobalGains = make([]int, len(inputs))
+	for i, r := range inputs {
+		if !t.include(i) {
+			continue
+		}
+
+		if level, ok := medianGlobalGain(r); ok {
+			t.maxGlobalGains[i] = level + t.thresholdSteps
+		}
+	}
+}
+
+// medianGlobalGain returns the median global gain of the granules with audio data of a stream.
+func medianGlobalGain(r io.Reader) (int, bool) {
+	var histogram [maxGlobalGain + 1]int
+	var count int
+	for {
+		frame := mp3lib.NextFrame(r)
+		if frame == nil {
+			break
+		}
+
+		granules, _ := sideInfoGranules(frame)
+		for _, g := range granules {
+			if g.hasEnergy() {
+				histogram[g.globalGain]++
+				count++
+			}
+		}
+	}
+
+	if count == 0 {
+		return 0, false
+	}
+
+	var below int
+	for gain, n := range histogram {
+		below += n
+		if below*2 >= count {
+			return gain, true
+		}
+	}
+
+	return maxGlobalGain, true
+}
+
+// isSilent returns true if none of the granules of the frame carries audio data at or above the
+// global gain. Frames of other layers than Layer III are never silent.
+func isSilent(frame *mp3lib.MP3Frame, maxGlobalGain int) bool {
+	granules, ok := sideInfoGranules(frame)
+	if !ok {
+		return false
+	}
+
+	for _, g := range granules {
+		if g.hasEnergy() && g.globalGain >= maxGlobalGain {
+			return false
+		}
+	}
+
+	return true
+}
+
+// granule is the side information of a granule (and channel) of a Layer III frame.
+type granule struct {
+	// position of the global gain in bits from the start of the frame
+	globalGainPos int
+	globalGain    int
+	// part2_3_length is not zero
+	hasData bool
+	// number of pairs of quantized values that are not limited to -1, 0 and 1
+	bigValues int
+}
+
+// hasEnergy returns true if the granule has audio data beyond the smallest quantized values (e.g.
+// not digital silence or noise at the quantization limit).
+func (g granule) hasEnergy() bool {
+	return g.hasData && g.bigValues > 0
+}
+
+const globalGainBits = 8
+
+// sideInfoGranules reads the global gain of the granules (and channels) from the side information
+// of a Layer III frame.
+func sideInfoGranules(frame *mp3lib.MP3Frame) ([]granule, bool) {
+	sideInfoSize := getSideInfoSize(frame)
+	offset := sideInfoOffset(frame)
+
+	if sideInfoSize == 0 || len(frame.RawBytes) < offset+sideInfoSize {
+		return nil, false
+	}
+
+	r := &bitReader{data: frame.RawBytes, pos: offset * 8}
+
+	channels := 2
+	if frame.ChannelMode == mp3lib.Mono {
+		channels = 1
+	}
+
+	count := 1
+	// main_data_begin, private_bits and for MPEG-1 the scale factor selection information
+	if frame.MPEGVersion == mp3lib.MPEGVersion1 {
+		count = 2
+		r.skip(9 + 5 - 2*(channels-1) + 4*channels)
+	} else {
+		r.skip(8 + channels)
+	}
+
+	granules := make([]granule, 0, count*channels)
+	for g := 0; g < count; g++ {
+		for c := 0; c < channels; c++ {
+			part23Length := r.read(12)
+			bigValues := r.read(9)
+			pos := r.pos
+			gain := r.read(globalGainBits)
+
+			// scalefac_compress, window_switching_flag, the block and region information and the flags
+			if frame.MPEGVersion == mp3lib.MPEGVersion1 {
+				r.skip(4 + 1 + 22 + 3)
+			} else {
+				r.skip(9 + 1 + 22 + 2)
+			}
+
+			granules = append(granules, granule{globalGainPos: pos, globalGain: gain, hasData: part23Length > 0, bigValues: bigValues})
+		}
+	}
+
+	return granules, true
+}
+
+// sideInfoOffset returns the position of the side information in bytes, after the header and the CRC.
+func sideInfoOffset(frame *mp3lib.MP3Frame) int {
+	if frame.CrcProtection {
+		return 4 + 2
+	}
+
+	return 4
+}
+
+// bitReader reads and writes big-endian bit fields.
+type bitReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *bitReader) skip(bits int) {
+	r.pos += bits
+}
+
+func (r *bitReader) read(bits int) int {
+	var value int
+	for i := 0; i < bits; i, r.pos = i+1, r.pos+1 {
+		bit := 0
+		if r.pos/8 < len(r.data) {
+			bit = int(r.data[r.pos/8]>>(7-r.pos%8)) & 1
+		}
+
+		value = value<<1 | bit
+	}
+
+	return value
+}
+
+// write writes the value to the bit field at the position.
+func (r *bitReader) write(pos, bits, value int) {
+	for i := 0; i < bits; i++ {
+		p := pos + i
+		if p/8 >= len(r.data) {
+			return
+		}
+
+		mask := byte(1 << (7 - p%8))
+		if value>>(bits-1-i)&1 == 1 {
+			r.data[p/8] |= mask
+		} else {
+			r.data[p/8] &^= mask
+		}
+	}
+}
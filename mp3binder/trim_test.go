@@ -0,0 +1,119 @@
+package mp3binder
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/dmulholl/mp3lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSilent(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []struct {
+		name     string
+		frame    *mp3lib.MP3Frame
+		expected bool
+	}{
+		{name: "digital silence", frame: makeFrame(t, header44100), expected: true},
+		{name: "silent frame", frame: func() *mp3lib.MP3Frame {
+			return mp3lib.NextFrame(bytes.NewReader(silentFrame(makeAudibleFrame(t, header44100, 1000, 100, 170))))
+		}(), expected: true},
+		{name: "no audio data with gain", frame: makeAudibleFrame(t, header44100, 0, 0, 210), expected: true},
+		{name: "no big values", frame: makeAudibleFrame(t, header44100, 40, 0, 170), expected: true},
+		{name: "quiet", frame: makeAudibleFrame(t, header44100, 300, 20, 129), expected: true},
+		{name: "at the threshold", frame: makeAudibleFrame(t, header44100, 300, 20, 130), expected: false},
+		{name: "loud", frame: makeAudibleFrame(t, header44100, 2000, 200, 180), expected: false},
+		{name: "quiet with CRC", frame: makeAudibleFrame(t, header44100CRC, 300, 20, 100), expected: true},
+		{name: "loud with CRC", frame: makeAudibleFrame(t, header44100CRC, 2000, 200, 180), expected: false},
+		{name: "quiet mono", frame: makeAudibleFrame(t, header22050Mono, 300, 20, 100), expected: true},
+		{name: "loud mono", frame: makeAudibleFrame(t, header22050Mono, 2000, 200, 180), expected: false},
+	} {
+		f := f // pin
+		t.Run(f.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, f.expected, isSilent(f.frame, 130))
+		})
+	}
+}
+
+func TestIsSilentWithOneLoudGranule(t *testing.T) {
+	t.Parallel()
+
+	frame := makeFrame(t, header44100)
+	granules, ok := sideInfoGranules(frame)
+	if !assert.True(t, ok) || !assert.Len(t, granules, 4) {
+		return
+	}
+	assert.True(t, isSilent(frame, 130))
+
+	// the second channel of the second granule
+	w := &bitReader{data: frame.RawBytes}
+	w.write(granules[3].globalGainPos-9-12, 12, 2000)
+	w.write(granules[3].globalGainPos-9, 9, 200)
+	w.write(granules[3].globalGainPos, globalGainBits, 180)
+	assert.False(t, isSilent(frame, 130))
+}
+
+func TestMedianGlobalGain(t *testing.T) {
+	t.Parallel()
+
+	silence := makeFrame(t, header44100)
+	quiet := makeAudibleFrame(t, header44100, 300, 20, 120)
+	loud := makeAudibleFrame(t, header44100, 2000, 200, 170)
+
+	input := append(stream(repeat(silence, 50)...), stream(quiet, quiet, loud, loud, loud)...)
+	gain, ok := medianGlobalGain(bytes.NewReader(input))
+	assert.True(t, ok)
+	assert.Equal(t, 170, gain, "granules without audio data are ignored")
+
+	_, ok = medianGlobalGain(bytes.NewReader(stream(repeat(silence, 10)...)))
+	assert.False(t, ok)
+}
+
+func TestTrimSilence(t *testing.T) {
+	t.Parallel()
+
+	silence := makeFrame(t, header44100)
+	// a fade-in far louder than the threshold of 40 steps below the level of the file
+	fadeIn := makeAudibleFrame(t, header44100, 400, 30, 150)
+	loud := makeAudibleFrame(t, header44100, 2000, 200, 170)
+	quiet := makeAudibleFrame(t, header44100, 300, 20, 120)
+	frameDuration := duration(loud)
+
+	var frames []*mp3lib.MP3Frame
+	frames = append(frames, repeat(silence, 5)...)
+	frames = append(frames, repeat(fadeIn, 18)...)
+	frames = append(frames, repeat(loud, 20)...)
+	// a pause is kept
+	frames = append(frames, repeat(silence, 2)...)
+	frames = append(frames, repeat(loud, 20)...)
+	frames = append(frames, repeat(quiet, 3)...)
+	frames = append(frames, repeat(silence, 4)...)
+	input := stream(frames...)
+
+	type trimmed struct {
+		index             int
+		leading, trailing time.Duration
+	}
+
+	var removed []trimmed
+	output, err := bind(t, [][]byte{input, input},
+		TrimSilence(-60, func(index int) bool { return index == 0 }, func(index int, leading, trailing time.Duration) {
+			removed = append(removed, trimmed{index, leading, trailing})
+		}))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []trimmed{{0, 5 * frameDuration, 7 * frameDuration}}, removed)
+
+		bound := audioFrames(t, output)
+		if assert.Len(t, bound, 18+20+2+20+len(frames)) {
+			assert.Equal(t, fadeIn.RawBytes, bound[0].RawBytes)
+			assert.Equal(t, silence.RawBytes, bound[18+20].RawBytes)
+			assert.Equal(t, loud.RawBytes, bound[18+20+2+20-1].RawBytes)
+			assert.Equal(t, silence.RawBytes, bound[18+20+2+20].RawBytes, "the excluded input file is not trimmed")
+		}
+	}
+}
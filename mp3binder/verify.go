@@ -0,0 +1,236 @@
+package mp3binder
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/crra/id3v2/v2"
+	"github.com/dmulholl/mp3lib"
+)
+
+// the chapter frame stores its times in milliseconds
+const chapterTolerance = time.Millisecond
+
+// Difference is a mismatch between a bound output file and its input files.
+type Difference struct {
+	// Input is the index of the input file, -1 if the difference concerns the output file only
+	Input   int
+	Message string
+}
+
+// Verify checks that the audio frames of the output file are identical to the audio frames of
+// the input files (without their Xing, Info or VBRI headers), that the chapters start and end with
+// the input files and that the Xing or Info header counts the frames and bytes of the output file.
+// The output file must be bound without changes of the frames (e.g. no gap, trimming or
+// normalization). It returns the differences, which are empty if the output file is valid.
+func Verify(output io.Reader, inputs []io.Reader) ([]Difference, error) {
+	out := &outputScanner{r: output, tag: id3v2.NewEmptyTag()}
+
+	var differences []Difference
+	differ := func(input int, format string, a ...any) {
+		differences = append(differences, Difference{Input: input, Message: fmt.Sprintf(format, a...)})
+	}
+
+	starts := make([]time.Duration, len(inputs))
+	ends := make([]time.Duration, len(inputs))
+
+Inputs:
+	for i, r := range inputs {
+		starts[i] = out.position
+
+		var frameIndex int
+		var position time.Duration
+		// the first differing frame is reported, the others are counted
+		var mismatches int
+		firstFrame := true
+
+		for {
+			frame := mp3lib.NextFrame(r)
+			if frame == nil {
+				break
+			}
+
+			if firstFrame && (mp3lib.IsXingHeader(frame) || mp3lib.IsVbriHeader(frame)) {
+				firstFrame = false
+				continue
+			}
+			firstFrame = false
+
+			outFrame := out.next()
+			switch {
+			case outFrame == nil:
+				differ(i, "the output file ends at frame %d (%s), but the input file continues with frame %d (%s)",
+					out.frames, out.position.Round(time.Millisecond), frameIndex, position.Round(time.Millisecond))
+
+				// the chapters can't be compared with the missing input files
+				starts = nil
+				break Inputs
+			case !bytes.Equal(frame.RawBytes, outFrame.RawBytes):
+				if mismatches == 0 {
+					differ(i, "frame %d (%s) of the input file differs from frame %d (%s) of the output file %s",
+						frameIndex, position.Round(time.Millisecond), out.frames-1, (out.position - duration(outFrame)).Round(time.Millisecond),
+						describeFrameDifference(frame.RawBytes, outFrame.RawBytes))
+				}
+				mismatches++
+			}
+
+			frameIndex++
+			position += duration(frame)
+		}
+
+		if mismatches > 1 {
+			differ(i, "%d of %d frames of the input file differ from the output file", mismatches, frameIndex)
+		}
+
+		ends[i] = starts[i] + position
+	}
+
+	if extra, extraDuration := out.rest(); extra > 0 {
+		differ(-1, "the output file has %d frames (%s) more than the input files", extra, extraDuration.Round(time.Millisecond))
+	}
+
+	if out.err != nil {
+		return differences, out.err
+	}
+
+	differences = append(differences, verifyHeader(out)...)
+	if starts != nil {
+		differences = append(differences, verifyChapters(out.tag, starts, ends)...)
+	}
+
+	return differences, nil
+}
+
+// describeFrameDifference describes the first differing byte of two frames.
+func describeFrameDifference(expected, actual []byte) string {
+	for i := 0; i < len(expected) && i < len(actual); i++ {
+		if expected[i] != actual[i] {
+			return fmt.Sprintf("at byte %d: expected 0x%02x, got 0x%02x", i, expected[i], actual[i])
+		}
+	}
+
+	return fmt.Sprintf("in length: expected %d bytes, got %d bytes", len(expected), len(actual))
+}
+
+// verifyHeader compares the number of frames and bytes of the Xing or Info header of the output
+// file with its audio frames.
+func verifyHeader(out *outputScanner) []Difference {
+	if out.header == nil || out.header.Kind == "VBRI" {
+		return []Difference{{Input: -1, Message: "the output file has no Xing or Info header"}}
+	}
+
+	var differences []Difference
+	if out.header.Frames != uint32(out.frames) {
+		differences = append(differences, Difference{Input: -1, Message: fmt.Sprintf("the %s header counts %d frames, the output file has %d frames", out.header.Kind, out.header.Frames, out.frames)})
+	}
+
+	if out.header.Bytes != uint32(out.bytes) {
+		differences = append(differences, Difference{Input: -1, Message: fmt.Sprintf("the %s header counts %d bytes, the frames of the output file have %d bytes", out.header.Kind, out.header.Bytes, out.bytes)})
+	}
+
+	return differences
+}
+
+// verifyChapters checks that each chapter starts and ends with an input file. Input files without
+// a chapter (e.g. an interlace file) are not reported.
+func verifyChapters(tag *id3v2.Tag, starts, ends []time.Duration) []Difference {
+	var differences []Difference
+
+	for _, f := range tag.GetFrames(tagChapter) {
+		chapter, ok := f.(id3v2.ChapterFrame)
+		if !ok {
+			continue
+		}
+
+		title := chapter.ElementID
+		if chapter.Title != nil && chapter.Title.Text != "" {
+			title = chapter.Title.Text
+		}
+
+		input := -1
+		for i := range starts {
+			if within(chapter.StartTime, starts[i], chapterTolerance) {
+				input = i
+				break
+			}
+		}
+
+		switch {
+		case input < 0:
+			differences = append(differences, Difference{Input: -1, Message: fmt.Sprintf("the chapter '%s' starts at %s, but no input file starts there", title, chapter.StartTime)})
+		case !within(chapter.EndTime, ends[input], chapterTolerance):
+			differences = append(differences, Difference{Input: input, Message: fmt.Sprintf("the chapter '%s' ends at %s, but the input file ends at %s", title, chapter.EndTime, ends[input].Round(time.Millisecond))})
+		}
+	}
+
+	return differences
+}
+
+// within returns true if the durations differ by less than the tolerance.
+func within(a, b, tolerance time.Duration) bool {
+	d := a - b
+	return d < tolerance && d > -tolerance
+}
+
+// outputScanner reads the audio frames of an output file and collects its tags and its header.
+type outputScanner struct {
+	r        io.Reader
+	tag      *id3v2.Tag
+	header   *BitrateHeader
+	frames   int
+	bytes    int
+	position time.Duration
+	started  bool
+	// the error of parsing a tag
+	err error
+}
+
+// next returns the next audio frame, nil at the end of the file.
+func (s *outputScanner) next() *mp3lib.MP3Frame {
+	for {
+		obj := mp3lib.NextObject(s.r)
+		if obj == nil {
+			return nil
+		}
+
+		switch obj := obj.(type) {
+		case *mp3lib.MP3Frame:
+			if !s.started {
+				s.started = true
+				if mp3lib.IsXingHeader(obj) || mp3lib.IsVbriHeader(obj) {
+					s.header = readBitrateHeader(obj)
+					continue
+				}
+			}
+
+			s.frames++
+			s.bytes += len(obj.RawBytes)
+			s.position += duration(obj)
+
+			return obj
+		case *mp3lib.ID3v2Tag:
+			tag, err := id3v2.ParseReader(bytes.NewReader(obj.RawBytes), id3v2.Options{Parse: true})
+			if err != nil {
+				s.err = err
+				continue
+			}
+
+			for id, frames := range tag.AllFrames() {
+				for _, f := range frames {
+					s.tag.AddFrame(id, f)
+				}
+			}
+		}
+	}
+}
+
+// rest reads the remaining audio frames and returns their number and duration.
+func (s *outputScanner) rest() (int, time.Duration) {
+	frames, position := s.frames, s.position
+	for s.next() != nil {
+	}
+
+	return s.frames - frames, s.position - position
+}
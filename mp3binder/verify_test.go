@@ -0,0 +1,98 @@
+package mp3binder
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/crra/id3v2/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// bindWithChapters binds the input streams with a chapter for each input file.
+func bindWithChapters(t *testing.T, inputs [][]byte) []byte {
+	t.Helper()
+
+	output, err := bind(t, inputs, Chapters(func(index, _ int, _ time.Duration) (bool, string) {
+		return true, []string{"One", "Two", "Three"}[index]
+	}))
+	if err != nil {
+		panic(err)
+	}
+
+	return output
+}
+
+// readers returns a reader for each input stream.
+func readers(inputs [][]byte) []io.Reader {
+	r := make([]io.Reader, len(inputs))
+	for i, in := range inputs {
+		r[i] = bytes.NewReader(in)
+	}
+
+	return r
+}
+
+// withChapters replaces the tag of a bound file with its tag whose chapters are changed.
+func withChapters(file []byte, change func(chapter *id3v2.ChapterFrame)) []byte {
+	tag, err := id3v2.ParseReader(bytes.NewReader(file), id3v2.Options{Parse: true})
+	if err != nil {
+		panic(err)
+	}
+
+	// the size of the tag is synchsafe and excludes the header
+	size := 10 + (int(file[6])<<21 | int(file[7])<<14 | int(file[8])<<7 | int(file[9]))
+	audio := file[size:]
+
+	chapters := tag.GetFrames(tagChapter)
+	tag.DeleteFrames(tagChapter)
+	for _, f := range chapters {
+		chapter := f.(id3v2.ChapterFrame)
+		change(&chapter)
+		tag.AddFrame(tagChapter, chapter)
+	}
+
+	var b bytes.Buffer
+	if _, err := tag.WriteTo(&b); err != nil {
+		panic(err)
+	}
+
+	return append(b.Bytes(), audio...)
+}
+
+func TestVerifyBoundFile(t *testing.T) {
+	t.Parallel()
+
+	frame := makeAudibleFrame(t, header44100, 1000, 100, 150)
+	inputs := [][]byte{stream(repeat(frame, 3)...), stream(repeat(frame, 5)...), stream(repeat(frame, 2)...)}
+	output := bindWithChapters(t, inputs)
+
+	differences, err := Verify(bytes.NewReader(output), readers(inputs))
+	if assert.NoError(t, err) {
+		assert.Empty(t, differences)
+	}
+}
+
+func TestVerifyTamperedChapters(t *testing.T) {
+	t.Parallel()
+
+	frame := makeAudibleFrame(t, header44100, 1000, 100, 150)
+	inputs := [][]byte{stream(repeat(frame, 3)...), stream(repeat(frame, 5)...), stream(repeat(frame, 2)...)}
+	output := withChapters(bindWithChapters(t, inputs), func(chapter *id3v2.ChapterFrame) {
+		switch chapter.Title.Text {
+		case "One":
+			chapter.EndTime -= 10 * time.Millisecond
+		case "Two":
+			chapter.StartTime += 10 * time.Millisecond
+		}
+	})
+
+	differences, err := Verify(bytes.NewReader(output), readers(inputs))
+	if assert.NoError(t, err) && assert.Len(t, differences, 2) {
+		assert.Equal(t, 0, differences[0].Input)
+		assert.Contains(t, differences[0].Message, "the chapter 'One' ends at")
+		assert.Equal(t, -1, differences[1].Input)
+		assert.Contains(t, differences[1].Message, "the chapter 'Two' starts at")
+	}
+}